@@ -0,0 +1,89 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: convert.go
+//         The -o/--from code path: turns the parsed document into JSON or
+//         YAML (see the encode package) instead of the colored tree, and
+//         can read a document back in from one of those formats to
+//         reconstruct plain XML, making the conversion round-trippable.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jschwartzman/xmlParse/encode"
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+func encodeOptions(attrPrefix, contentKey string) encode.Options {
+	opts := encode.DefaultOptions()
+	if attrPrefix != "" {
+		opts.AttrPrefix = attrPrefix
+	}
+	if contentKey != "" {
+		opts.ContentKey = contentKey
+	}
+	return opts
+}
+
+// convertRenderer is the Renderer for -o/--from: it builds the document's
+// generic tree (from XML, or from JSON/YAML when from is set) and renders
+// it in the "to" format ("json", "yaml", or "xml"/"" for plain reconstructed
+// XML).
+type convertRenderer struct {
+	from, to string
+	opts     encode.Options
+}
+
+func (r *convertRenderer) Render(xmlFile io.Reader, w io.Writer) (int, error) {
+	name, value, err := readTree(xmlFile, r.from, r.opts)
+	if err != nil {
+		return 0, err
+	}
+
+	switch r.to {
+	case "json":
+		data, err := encode.ToJSON(name, value)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprintln(w, string(data))
+
+	case "yaml":
+		data, err := encode.ToYAML(name, value)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprint(w, string(data))
+
+	case "xml", "":
+		if err := encode.WriteXML(w, name, value, r.opts); err != nil {
+			return 0, err
+		}
+
+	default:
+		return 0, fmt.Errorf("unknown output format %q (want json, yaml, or xml)", r.to)
+	}
+	return 0, nil
+}
+
+func readTree(xmlFile io.Reader, fromFormat string, opts encode.Options) (string, interface{}, error) {
+	if fromFormat == "" {
+		parser := pullparser.New(xmlFile)
+		return encode.Build(parser, opts)
+	}
+
+	data, err := io.ReadAll(xmlFile)
+	if err != nil {
+		return "", nil, err
+	}
+	switch fromFormat {
+	case "json":
+		return encode.FromJSON(data)
+	case "yaml":
+		return encode.FromYAML(data)
+	default:
+		return "", nil, fmt.Errorf("unknown --from format %q (want json or yaml)", fromFormat)
+	}
+}
@@ -0,0 +1,56 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: json.go
+//         JSON/YAML encode and decode of the generic tree built by
+//         Build/buildElement, wrapped under its root element's name.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package encode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToJSON renders the document (name -> value, as returned by Build) as
+// indented JSON.
+func ToJSON(name string, value interface{}) ([]byte, error) {
+	doc := map[string]interface{}{name: value}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// ToYAML renders the document as YAML.
+func ToYAML(name string, value interface{}) ([]byte, error) {
+	doc := map[string]interface{}{name: value}
+	return yaml.Marshal(doc)
+}
+
+// FromJSON parses data produced by ToJSON (or any single-root-key JSON
+// object following the same convention) back into a (name, value) pair.
+func FromJSON(data []byte) (name string, value interface{}, err error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", nil, err
+	}
+	return splitRoot(doc)
+}
+
+// FromYAML is FromJSON's YAML counterpart.
+func FromYAML(data []byte) (name string, value interface{}, err error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "", nil, err
+	}
+	return splitRoot(doc)
+}
+
+func splitRoot(doc map[string]interface{}) (string, interface{}, error) {
+	if len(doc) != 1 {
+		return "", nil, fmt.Errorf("encode: expected a single root key, found %d", len(doc))
+	}
+	for k, v := range doc {
+		return k, v, nil
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,138 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: tree.go
+//         Builds a generic, JSON/YAML-friendly tree out of a pullparser
+//         stream, following the convention popularized by yq's XML mode:
+//         attributes become prefixed keys, repeated children collapse into
+//         arrays, and a leaf element with no attributes or children reduces
+//         to a plain scalar. WriteXML (in xml.go) reverses the conversion,
+//         making it round-trippable.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package encode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+// Options controls how the element/attribute/text conventions are named.
+type Options struct {
+	AttrPrefix   string // prefix for attribute keys, default "+"
+	ContentKey   string // key holding an element's own text, default "#text"
+	KeepComments bool   // preserve comments under "#comment"
+}
+
+// CommentKey and DirectiveKey are fixed, unlike AttrPrefix/ContentKey, since
+// requests never asked for them to be configurable.
+const (
+	CommentKey   = "#comment"
+	DirectiveKey = "#directive"
+)
+
+// DefaultOptions returns the tool's default naming convention.
+func DefaultOptions() Options {
+	return Options{AttrPrefix: "+", ContentKey: "#text", KeepComments: true}
+}
+
+// Build reads the document element from p and returns its local name
+// together with its generic tree representation. Any prolog content before
+// the root element (the XML declaration, leading comments/PIs) is skipped.
+func Build(p *pullparser.XMLPullParser, opts Options) (name string, value interface{}, err error) {
+	for {
+		ev, err := p.Next()
+		if err != nil {
+			return "", nil, err
+		}
+		switch ev {
+		case pullparser.StartTag:
+			name := p.Name
+			v, err := buildElement(p, opts)
+			return name, v, err
+		case pullparser.EndDocument:
+			return "", nil, fmt.Errorf("encode: no root element found")
+		}
+	}
+}
+
+// buildElement is called with p positioned on the element's StartTag, and
+// returns with p positioned on the matching EndTag.
+func buildElement(p *pullparser.XMLPullParser, opts Options) (interface{}, error) {
+	result := map[string]interface{}{}
+	for _, a := range p.Attrs {
+		result[opts.AttrPrefix+a.Name] = a.Value
+	}
+
+	var text strings.Builder
+	var comments []string
+	var directives []string
+
+	for {
+		ev, err := p.Next()
+		if err != nil {
+			return nil, err
+		}
+		if ev == pullparser.EndTag {
+			break
+		}
+		switch ev {
+		case pullparser.StartTag:
+			childName := p.Name
+			childVal, err := buildElement(p, opts)
+			if err != nil {
+				return nil, err
+			}
+			addChild(result, childName, childVal)
+		case pullparser.Text:
+			text.WriteString(p.Text)
+		case pullparser.Comment:
+			if opts.KeepComments {
+				comments = append(comments, p.Text)
+			}
+		case pullparser.ProcessingInstruction:
+			directives = append(directives, p.Name+" "+p.Text) // keep the target so WriteXML can rebuild <?target inst?>
+		case pullparser.Directive:
+			directives = append(directives, p.Text)
+		}
+	}
+
+	if len(comments) == 1 {
+		result[CommentKey] = comments[0]
+	} else if len(comments) > 1 {
+		result[CommentKey] = comments
+	}
+	if len(directives) == 1 {
+		result[DirectiveKey] = directives[0]
+	} else if len(directives) > 1 {
+		result[DirectiveKey] = directives
+	}
+
+	trimmed := strings.TrimSpace(text.String())
+	if trimmed != "" {
+		if len(result) == 0 {
+			return trimmed, nil // a leaf with only text reduces to a scalar
+		}
+		result[opts.ContentKey] = trimmed
+	}
+	if len(result) == 0 {
+		return "", nil // empty element
+	}
+	return result, nil
+}
+
+// addChild folds a newly built child into result, collapsing repeated
+// element names into a []interface{} as the second and later occurrences
+// arrive.
+func addChild(result map[string]interface{}, name string, val interface{}) {
+	existing, ok := result[name]
+	if !ok {
+		result[name] = val
+		return
+	}
+	if arr, ok := existing.([]interface{}); ok {
+		result[name] = append(arr, val)
+		return
+	}
+	result[name] = []interface{}{existing, val}
+}
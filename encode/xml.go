@@ -0,0 +1,137 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: xml.go
+//         Reconstructs XML text from the generic tree built by Build, the
+//         other half of the round trip: "xmlParse -o json" then
+//         "xmlParse --from json -o xml" should reproduce an equivalent
+//         document (element/attribute order is not preserved, since the
+//         tree in between is keyed by plain Go maps; namespace prefixes
+//         and xmlns declarations are not carried through the tree, so
+//         the rebuilt document uses local names only).
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package encode
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteXML writes value (as returned by Build/FromJSON/FromYAML) to w as an
+// indented XML document rooted at name.
+func WriteXML(w io.Writer, name string, value interface{}, opts Options) error {
+	return writeNode(w, name, value, opts, 0)
+}
+
+func writeNode(w io.Writer, name string, value interface{}, opts Options, depth int) error {
+	indent := strings.Repeat("  ", depth)
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if err := writeNode(w, name, item, opts, depth); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case map[string]interface{}:
+		return writeElement(w, name, v, opts, depth)
+
+	default:
+		text := scalarText(v)
+		if text == "" {
+			fmt.Fprintf(w, "%s<%s/>\n", indent, name)
+			return nil
+		}
+		fmt.Fprintf(w, "%s<%s>%s</%s>\n", indent, name, escapeText(text), name)
+		return nil
+	}
+}
+
+func writeElement(w io.Writer, name string, fields map[string]interface{}, opts Options, depth int) error {
+	indent := strings.Repeat("  ", depth)
+
+	var attrNames, childNames []string
+	for k := range fields {
+		switch {
+		case strings.HasPrefix(k, opts.AttrPrefix) && k != opts.ContentKey:
+			attrNames = append(attrNames, k)
+		case k == opts.ContentKey, k == CommentKey, k == DirectiveKey:
+			// handled separately below
+		default:
+			childNames = append(childNames, k)
+		}
+	}
+	sort.Strings(attrNames)
+	sort.Strings(childNames)
+
+	fmt.Fprintf(w, "%s<%s", indent, name)
+	for _, k := range attrNames {
+		attrName := strings.TrimPrefix(k, opts.AttrPrefix)
+		fmt.Fprintf(w, " %s=\"%s\"", attrName, escapeText(scalarText(fields[k])))
+	}
+
+	content, hasContent := fields[opts.ContentKey]
+	if len(childNames) == 0 && !hasContent {
+		if _, hasComment := fields[CommentKey]; !hasComment {
+			if _, hasDirective := fields[DirectiveKey]; !hasDirective {
+				fmt.Fprintf(w, "/>\n")
+				return nil
+			}
+		}
+	}
+	fmt.Fprintf(w, ">\n")
+
+	for _, c := range toStrings(fields[CommentKey]) {
+		fmt.Fprintf(w, "%s  <!--%s-->\n", indent, c)
+	}
+	for _, d := range toStrings(fields[DirectiveKey]) {
+		fmt.Fprintf(w, "%s  <?%s?>\n", indent, d)
+	}
+	if hasContent {
+		fmt.Fprintf(w, "%s  %s\n", indent, escapeText(scalarText(content)))
+	}
+	for _, k := range childNames {
+		if err := writeNode(w, k, fields[k], opts, depth+1); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(w, "%s</%s>\n", indent, name)
+	return nil
+}
+
+func toStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			out = append(out, scalarText(item))
+		}
+		return out
+	default:
+		return []string{scalarText(t)}
+	}
+}
+
+func scalarText(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func escapeText(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,176 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: job.go
+//         Drives one or more input files through a Renderer concurrently.
+//         Each file is a Job: a worker pool sized by --jobs renders it into
+//         its own buffer (so parallel workers never interleave writes to
+//         stdout), then the buffers are flushed to stdout in input order so
+//         output stays deterministic regardless of which job finished first.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Renderer renders one XML input to w and reports how many problems it
+// found (e.g. schema violations); renderers with no such notion, like the
+// tree printer, always report 0. This is the seam that lets the worker pool
+// run the colored tree, the XPath query, the JSON/YAML/XML converter, and
+// the schema validator behind the same concurrent multi-file machinery.
+type Renderer interface {
+	Render(xmlFile io.Reader, w io.Writer) (problems int, err error)
+}
+
+// jobResult is one file's outcome, collected by a worker and later flushed
+// to stdout in input order by reportResults.
+type jobResult struct {
+	path     string
+	output   string
+	problems int
+	err      error
+	skipped  bool
+	elapsed  time.Duration
+}
+
+// expandPaths turns the command line's file arguments into a concrete,
+// ordered list of paths: "-" is left alone (meaning stdin), plain paths are
+// passed through untouched (so a missing file still fails with a normal
+// open error later), and anything containing glob metacharacters is
+// expanded with filepath.Glob.
+func expandPaths(args []string) ([]string, error) {
+	var paths []string
+	for _, a := range args {
+		if a == "-" || !strings.ContainsAny(a, "*?[") {
+			paths = append(paths, a)
+			continue
+		}
+		matches, err := filepath.Glob(a)
+		if err != nil {
+			return nil, fmt.Errorf("bad glob pattern %q: %w", a, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("glob pattern %q matched no files", a)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// runJobs renders every path through renderer using a pool of jobs workers,
+// prints the results in input order, and returns the process exit code.
+func runJobs(paths []string, renderer Renderer, jobs int, keepGoing bool) int {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	results := make([]jobResult, len(paths))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	var aborted int32
+
+	wallStart := time.Now()
+	for i, path := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if atomic.LoadInt32(&aborted) != 0 {
+				results[i] = jobResult{path: path, skipped: true}
+				return
+			}
+
+			start := time.Now()
+			var buf bytes.Buffer
+			problems, err := renderOne(path, renderer, &buf)
+			results[i] = jobResult{path: path, output: buf.String(), problems: problems, err: err, elapsed: time.Since(start)}
+			if err != nil && !keepGoing {
+				atomic.StoreInt32(&aborted, 1)
+			}
+		}(i, path)
+	}
+	wg.Wait()
+
+	return reportResults(results, time.Since(wallStart))
+}
+
+// renderOne opens path (or stdin, for "-") and renders it through renderer.
+func renderOne(path string, renderer Renderer, w io.Writer) (int, error) {
+	r, closer, err := openInput(path)
+	if err != nil {
+		return 0, err
+	}
+	defer closer()
+	return renderer.Render(r, w)
+}
+
+func openInput(path string) (io.Reader, func(), error) {
+	if path == "-" {
+		return os.Stdin, func() {}, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	return f, func() { f.Close() }, nil
+}
+
+// reportResults flushes every job's buffered output to stdout in input
+// order, wrapping each in a per-file header/footer when there's more than
+// one file, and returns the process exit code.
+func reportResults(results []jobResult, wallTime time.Duration) int {
+	multi := len(results) > 1
+	var failed, skipped, totalProblems int
+
+	for _, r := range results {
+		if r.skipped {
+			skipped++
+			if multi {
+				fmt.Printf("==> %s <==\nskipped (earlier failure, --keep-going not set)\n\n", r.path)
+			}
+			continue
+		}
+		if multi {
+			fmt.Printf("==> %s <==\n", r.path)
+		}
+		if r.err != nil {
+			failed++
+			fmt.Printf("Error: %s\n", r.err)
+		} else {
+			fmt.Print(r.output)
+			totalProblems += r.problems
+		}
+		if multi {
+			fmt.Println()
+		}
+	}
+
+	if multi {
+		fmt.Printf("%d file(s), %d failed, %d skipped, %d problem(s), %s\n",
+			len(results), failed, skipped, totalProblems, wallTime.Round(time.Millisecond))
+	}
+
+	if len(results) == 1 {
+		r := results[0]
+		if r.err != nil {
+			return 3
+		}
+		return r.problems
+	}
+	if failed > 0 || totalProblems > 0 {
+		return 1
+	}
+	return 0
+}
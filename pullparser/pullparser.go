@@ -0,0 +1,308 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: pullparser.go
+//         A reusable pull-parser over encoding/xml's SAX-style token stream.
+//         Wraps an xml.Decoder and exposes typed events plus namespace
+//         tracking so callers can build feed readers, DOM-like tools, or
+//         pretty-printers without re-implementing the token loop.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package pullparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EventType identifies the kind of node the parser last read.
+type EventType int
+
+// The set of event types produced by Next/NextTag.
+const (
+	StartDocument EventType = iota
+	EndDocument
+	StartTag
+	EndTag
+	Text
+	Comment
+	ProcessingInstruction
+	Directive
+	IgnorableWhitespace
+)
+
+// String renders an EventType as its name, e.g. "StartTag".
+func (e EventType) String() string {
+	switch e {
+	case StartDocument:
+		return "StartDocument"
+	case EndDocument:
+		return "EndDocument"
+	case StartTag:
+		return "StartTag"
+	case EndTag:
+		return "EndTag"
+	case Text:
+		return "Text"
+	case Comment:
+		return "Comment"
+	case ProcessingInstruction:
+		return "ProcessingInstruction"
+	case Directive:
+		return "Directive"
+	case IgnorableWhitespace:
+		return "IgnorableWhitespace"
+	default:
+		return "Unknown"
+	}
+}
+
+// Attr is a single attribute, with its namespace already split from its
+// local name.
+type Attr struct {
+	Space string // namespace URI, as resolved by encoding/xml (not a display prefix); empty if unqualified
+	Name  string // local name
+	Value string
+}
+
+// nsScope is one entry of the namespace stack: the prefix->URI bindings
+// declared by a single element.
+type nsScope map[string]string
+
+// XMLPullParser reads an XML document as a stream of typed events, tracking
+// element depth, the current node, and in-scope xmlns declarations.
+type XMLPullParser struct {
+	decoder *xml.Decoder
+
+	Event EventType // the event produced by the most recent Next/NextTag
+	Depth int       // number of open elements: 1 on the document element's StartTag, 0 after its EndTag (an EndTag has already been popped)
+	Name  string    // local name of the current start/end tag, or the target of the current processing instruction
+	Space string    // namespace prefix of the current start/end tag, if any
+	Attrs []Attr    // attributes of the current start tag
+	Text  string    // text/comment/directive/PI data for the current event
+
+	spaceURI string    // namespace URI of the current start/end tag, as encoding/xml resolved it (Space is the display prefix derived from it)
+	nsStack  []nsScope // one scope per open element, innermost last
+
+	started bool
+	ended   bool
+}
+
+// New wraps r in an xml.Decoder and returns a parser positioned before the
+// first token.
+func New(r io.Reader) *XMLPullParser {
+	return &XMLPullParser{decoder: xml.NewDecoder(r)}
+}
+
+// Offset returns the input byte offset just past the current event (the end
+// of the most recently read token), as reported by the underlying
+// xml.Decoder. Callers that buffer the original bytes can use
+// it to translate an event into a line/column position.
+func (p *XMLPullParser) Offset() int64 {
+	return p.decoder.InputOffset()
+}
+
+// Next advances the parser to the next event, including whitespace-only
+// text nodes. It returns the EventType of the new current node, or
+// EndDocument (with a nil error) once the stream is exhausted.
+func (p *XMLPullParser) Next() (EventType, error) {
+	if !p.started {
+		p.started = true
+		p.Event = StartDocument
+		return p.Event, nil
+	}
+	if p.ended {
+		return EndDocument, nil
+	}
+
+	t, err := p.decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			p.ended = true
+			p.Event = EndDocument
+			return p.Event, nil
+		}
+		return p.Event, err
+	}
+
+	switch tok := t.(type) {
+	case xml.StartElement:
+		p.pushNamespaces(tok)
+		p.Depth++
+		p.Name = tok.Name.Local
+		p.spaceURI = tok.Name.Space
+		p.Space = p.resolvePrefix(tok.Name.Space)
+		p.Attrs = attrsOf(tok.Attr)
+		p.Text = ""
+		p.Event = StartTag
+
+	case xml.EndElement:
+		p.Name = tok.Name.Local
+		p.spaceURI = tok.Name.Space
+		p.Space = p.resolvePrefix(tok.Name.Space)
+		p.Attrs = nil
+		p.Text = ""
+		p.Event = EndTag
+		p.Depth--
+		p.popNamespaces()
+
+	case xml.CharData:
+		p.Text = string(tok)
+		if strings.TrimSpace(p.Text) == "" {
+			p.Event = IgnorableWhitespace
+		} else {
+			p.Event = Text
+		}
+
+	case xml.Comment:
+		p.Text = string(tok)
+		p.Event = Comment
+
+	case xml.ProcInst:
+		p.Name = tok.Target
+		p.Text = string(tok.Inst)
+		p.Event = ProcessingInstruction
+
+	case xml.Directive:
+		p.Text = string(tok)
+		p.Event = Directive
+	}
+
+	return p.Event, nil
+}
+
+// NextTag advances past any Text, Comment, ProcessingInstruction, Directive,
+// or IgnorableWhitespace events and returns the next StartTag, EndTag, or
+// EndDocument event. It is the usual entry point when only element
+// structure matters.
+func (p *XMLPullParser) NextTag() (EventType, error) {
+	for {
+		ev, err := p.Next()
+		if err != nil {
+			return ev, err
+		}
+		switch ev {
+		case StartTag, EndTag, EndDocument:
+			return ev, nil
+		}
+	}
+}
+
+// IsWhitespace reports whether the current event is text made up entirely
+// of whitespace.
+func (p *XMLPullParser) IsWhitespace() bool {
+	return p.Event == IgnorableWhitespace
+}
+
+// Skip consumes events up to and including the EndTag that matches the
+// current StartTag, discarding everything in between. It is a no-op unless
+// the parser is positioned on a StartTag.
+func (p *XMLPullParser) Skip() error {
+	if p.Event != StartTag {
+		return nil
+	}
+	depth := p.Depth
+	for {
+		ev, err := p.NextTag()
+		if err != nil {
+			return err
+		}
+		if ev == EndDocument {
+			return io.ErrUnexpectedEOF
+		}
+		if ev == EndTag && p.Depth == depth-1 {
+			return nil
+		}
+	}
+}
+
+// DecodeElement decodes the element the parser is currently positioned on
+// (and its children) into v, delegating to encoding/xml. The parser must be
+// positioned on a StartTag; afterwards it is positioned on the
+// corresponding EndTag.
+func (p *XMLPullParser) DecodeElement(v interface{}) error {
+	if p.Event != StartTag {
+		return fmt.Errorf("pullparser: DecodeElement called while not positioned on a StartTag")
+	}
+	start := xml.StartElement{
+		Name: xml.Name{Local: p.Name, Space: p.spaceURI},
+		Attr: toXMLAttr(p.Attrs),
+	}
+	if err := p.decoder.DecodeElement(v, &start); err != nil {
+		return err
+	}
+	// DecodeElement consumes through the matching EndElement itself; bring
+	// our bookkeeping back in sync with it.
+	p.popNamespaces()
+	p.Depth--
+	p.Event = EndTag
+	return nil
+}
+
+// ResolveNamespace looks up the URI bound to prefix in the current scope,
+// searching outward from the innermost element. It returns "" if the
+// prefix is not bound.
+func (p *XMLPullParser) ResolveNamespace(prefix string) string {
+	for i := len(p.nsStack) - 1; i >= 0; i-- {
+		if uri, ok := p.nsStack[i][prefix]; ok {
+			return uri
+		}
+	}
+	return ""
+}
+
+func (p *XMLPullParser) pushNamespaces(se xml.StartElement) {
+	scope := nsScope{}
+	for _, a := range se.Attr {
+		switch {
+		case a.Name.Space == "xmlns":
+			scope[a.Name.Local] = a.Value
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			scope[""] = a.Value
+		}
+	}
+	p.nsStack = append(p.nsStack, scope)
+}
+
+func (p *XMLPullParser) popNamespaces() {
+	if len(p.nsStack) > 0 {
+		p.nsStack = p.nsStack[:len(p.nsStack)-1]
+	}
+}
+
+// resolvePrefix maps a Go encoding/xml namespace URI/name back to the
+// shorthand prefix in scope for it, falling back to the raw value when no
+// binding matches (encoding/xml itself resolves xmlns:foo to its URI, not
+// the prefix, so this recovers the prefix callers usually want to display).
+func (p *XMLPullParser) resolvePrefix(space string) string {
+	if space == "" {
+		return ""
+	}
+	for i := len(p.nsStack) - 1; i >= 0; i-- {
+		for prefix, uri := range p.nsStack[i] {
+			if uri == space {
+				return prefix
+			}
+		}
+	}
+	return space
+}
+
+func attrsOf(xattrs []xml.Attr) []Attr {
+	attrs := make([]Attr, 0, len(xattrs))
+	for _, a := range xattrs {
+		if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+			continue // namespace declarations are tracked separately
+		}
+		attrs = append(attrs, Attr{Space: a.Name.Space, Name: a.Name.Local, Value: a.Value})
+	}
+	return attrs
+}
+
+func toXMLAttr(attrs []Attr) []xml.Attr {
+	xattrs := make([]xml.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		xattrs = append(xattrs, xml.Attr{Name: xml.Name{Space: a.Space, Local: a.Name}, Value: a.Value})
+	}
+	return xattrs
+}
@@ -0,0 +1,161 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: pullparser_test.go
+//         Covers the event stream, namespace tracking, and DecodeElement --
+//         the foundation every later package (xpath, encode, validate)
+//         builds on.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package pullparser
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestNextEventSequence(t *testing.T) {
+	p := New(strings.NewReader(`<root a="1"><child>text</child><!--c--></root>`))
+
+	var events []EventType
+	for {
+		ev, err := p.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		events = append(events, ev)
+		if ev == EndDocument {
+			break
+		}
+	}
+
+	want := []EventType{
+		StartDocument, StartTag, StartTag, Text, EndTag, Comment, EndTag, EndDocument,
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %v events, want %v", events, want)
+	}
+	for i, ev := range events {
+		if ev != want[i] {
+			t.Errorf("event %d: got %s, want %s", i, ev, want[i])
+		}
+	}
+}
+
+func TestStartTagDepthNameAttrs(t *testing.T) {
+	p := New(strings.NewReader(`<root><child a="1" b="2"/></root>`))
+
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "root" || p.Depth != 1 {
+		t.Fatalf("root start: ev=%v name=%q depth=%d err=%v", ev, p.Name, p.Depth, err)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "child" || p.Depth != 2 {
+		t.Fatalf("child start: ev=%v name=%q depth=%d err=%v", ev, p.Name, p.Depth, err)
+	}
+	if len(p.Attrs) != 2 || p.Attrs[0].Name != "a" || p.Attrs[0].Value != "1" || p.Attrs[1].Name != "b" || p.Attrs[1].Value != "2" {
+		t.Fatalf("unexpected attrs: %+v", p.Attrs)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != EndTag || p.Name != "child" || p.Depth != 1 {
+		t.Fatalf("child end: ev=%v name=%q depth=%d err=%v", ev, p.Name, p.Depth, err)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != EndTag || p.Name != "root" || p.Depth != 0 {
+		t.Fatalf("root end: ev=%v name=%q depth=%d err=%v", ev, p.Name, p.Depth, err)
+	}
+}
+
+func TestNamespacePrefixAndResolve(t *testing.T) {
+	p := New(strings.NewReader(`<root xmlns:ex="http://example.com/ns"><ex:item/></root>`))
+
+	if ev, err := p.NextTag(); err != nil || ev != StartTag {
+		t.Fatalf("root start: %v %v", ev, err)
+	}
+	if uri := p.ResolveNamespace("ex"); uri != "http://example.com/ns" {
+		t.Fatalf("ResolveNamespace(ex) = %q, want the bound URI", uri)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "item" {
+		t.Fatalf("item start: ev=%v name=%q err=%v", ev, p.Name, err)
+	}
+	if p.Space != "ex" {
+		t.Errorf("Space = %q, want the display prefix %q", p.Space, "ex")
+	}
+
+	if ev, err := p.NextTag(); err != nil || ev != EndTag {
+		t.Fatalf("item end: %v %v", ev, err)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != EndTag {
+		t.Fatalf("root end: %v %v", ev, err)
+	}
+	if uri := p.ResolveNamespace("ex"); uri != "" {
+		t.Errorf("ResolveNamespace(ex) after root closed = %q, want empty (out of scope)", uri)
+	}
+}
+
+func TestIsWhitespace(t *testing.T) {
+	p := New(strings.NewReader("<root>   <child/></root>"))
+	if ev, err := p.NextTag(); err != nil || ev != StartTag {
+		t.Fatalf("root start: %v %v", ev, err)
+	}
+	ev, err := p.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if ev != IgnorableWhitespace || !p.IsWhitespace() {
+		t.Fatalf("got event %s, IsWhitespace=%v; want IgnorableWhitespace text", ev, p.IsWhitespace())
+	}
+}
+
+func TestSkip(t *testing.T) {
+	p := New(strings.NewReader(`<root><skip><a/><b/></skip><after/></root>`))
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "root" {
+		t.Fatalf("root start: %v %v %v", ev, p.Name, err)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "skip" {
+		t.Fatalf("skip start: %v %v %v", ev, p.Name, err)
+	}
+	if err := p.Skip(); err != nil {
+		t.Fatalf("Skip: %v", err)
+	}
+	if p.Event != EndTag || p.Name != "skip" {
+		t.Fatalf("after Skip: event=%s name=%q, want positioned on skip's EndTag", p.Event, p.Name)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "after" {
+		t.Fatalf("after start: %v %v %v", ev, p.Name, err)
+	}
+}
+
+type nsItem struct {
+	XMLName xml.Name `xml:"http://example.com/ns item"`
+	Text    string   `xml:",chardata"`
+}
+
+// TestDecodeElementNamespace reconstructs the xml.StartElement DecodeElement
+// hands to encoding/xml using the resolved namespace URI (what
+// encoding/xml's own matching expects), not the display prefix pullparser
+// keeps in Space for callers that print namespaced names.
+func TestDecodeElementNamespace(t *testing.T) {
+	p := New(strings.NewReader(`<root xmlns:ex="http://example.com/ns"><ex:item>hello</ex:item></root>`))
+
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "root" {
+		t.Fatalf("root start: %v %v %v", ev, p.Name, err)
+	}
+	if ev, err := p.NextTag(); err != nil || ev != StartTag || p.Name != "item" {
+		t.Fatalf("item start: %v %v %v", ev, p.Name, err)
+	}
+
+	var it nsItem
+	if err := p.DecodeElement(&it); err != nil {
+		t.Fatalf("DecodeElement: %v", err)
+	}
+	if it.Text != "hello" {
+		t.Errorf("Text = %q, want %q", it.Text, "hello")
+	}
+	if p.Event != EndTag || p.Name != "item" {
+		t.Fatalf("after DecodeElement: event=%s name=%q, want positioned on item's EndTag", p.Event, p.Name)
+	}
+}
+
+func TestDecodeElementRequiresStartTag(t *testing.T) {
+	p := New(strings.NewReader(`<root/>`))
+	var v struct{}
+	if err := p.DecodeElement(&v); err == nil {
+		t.Fatal("DecodeElement before any Next() call should error, not panic or silently succeed")
+	}
+}
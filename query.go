@@ -0,0 +1,92 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: query.go
+//         The -q/--query code path: evaluates one or more XPath expressions
+//         against the streamed document (see the xpath package) and prints
+//         only the matching nodes, using the same colored writers as the
+//         full-tree printer in xmlParse.go.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+	"github.com/jschwartzman/xmlParse/xpath"
+)
+
+// queryRenderer is the Renderer for -q/--query: it prints every node
+// matching exprs, or (with countOnly) just the match count.
+type queryRenderer struct {
+	exprs     []string
+	countOnly bool
+}
+
+func (r *queryRenderer) Render(xmlFile io.Reader, w io.Writer) (int, error) {
+	compiled, err := xpath.Compile(r.exprs)
+	if err != nil {
+		return 0, err
+	}
+
+	parser := pullparser.New(xmlFile)
+	matcher := xpath.NewMatcher(compiled)
+	tw := newTreeWriter(w)
+
+	count, err := matcher.Eval(parser, func(n *xpath.Node) {
+		if !r.countOnly {
+			printMatch(tw, n)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if r.countOnly {
+		fmt.Fprintf(w, "%d\n", count)
+		return 0, nil
+	}
+	fmt.Fprintf(w, white) // restore normal screen formatting
+	tw.newLine()
+	return 0, nil
+}
+
+// printMatch renders one matched xpath.Node using the tool's usual colored
+// element/attribute/text writers.
+func printMatch(tw *treeWriter, n *xpath.Node) {
+	switch n.Kind {
+	case xpath.AttrNode:
+		tw.newLine()
+		tw.attribute(n.Name, n.Text)
+	case xpath.TextNode:
+		tw.newLine()
+		tw.characterData(n.Text)
+	default:
+		printElement(tw, n, 0)
+	}
+}
+
+// printElement writes a matched element subtree, re-indenting it as if it
+// were the root of its own document.
+func printElement(tw *treeWriter, n *xpath.Node, depth int) {
+	tw.newLine()
+	tw.spacing(depth, "   ")
+	tw.startName(n.Name)
+	for _, a := range n.Attrs {
+		tw.attribute(a.Name, a.Value)
+	}
+	tw.lastWritePos = depth
+
+	if text := strings.TrimSpace(n.Text); text != "" {
+		tw.characterData(text)
+	}
+	for _, child := range n.Children {
+		printElement(tw, child, depth+1)
+	}
+
+	if depth < tw.lastWritePos {
+		tw.spacing(depth, "   ")
+	}
+	tw.endName(n.Name)
+}
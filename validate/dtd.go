@@ -0,0 +1,257 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: dtd.go
+//         Parses a minimal DTD subset into a Schema: ELEMENT content models
+//         (sequence/choice groups, ?/*/+ occurrence suffixes, #PCDATA mixed
+//         content, EMPTY, ANY) and ATTLIST #REQUIRED/#IMPLIED attributes.
+//         A nested sequence group is flattened to its leaf element names,
+//         each taking the group's own occurrence suffix; a nested choice
+//         group becomes one particle whose alternatives are its leaf
+//         names — good enough to catch missing/unknown/over-repeated
+//         elements, though it loses strict sub-sequencing inside a
+//         nested group.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// LoadDTD reads a DTD from r and compiles it into a Schema.
+func LoadDTD(r io.Reader) (*Schema, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	sc := &Schema{Elements: map[string]*ElementDecl{}}
+	for _, decl := range splitDecls(stripDTDComments(string(data))) {
+		body := strings.TrimSpace(decl)
+		switch {
+		case strings.HasPrefix(body, "ELEMENT"):
+			parseElementDecl(sc, strings.TrimSpace(body[len("ELEMENT"):]))
+		case strings.HasPrefix(body, "ATTLIST"):
+			parseAttlistDecl(sc, strings.TrimSpace(body[len("ATTLIST"):]))
+		}
+	}
+	return sc, nil
+}
+
+var dtdCommentRE = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+func stripDTDComments(s string) string {
+	return dtdCommentRE.ReplaceAllString(s, "")
+}
+
+// splitDecls returns the body of each "<!...>" markup declaration, without
+// the surrounding "<!" and ">". DTD declarations never contain a literal
+// ">" inside their body, so a simple scan suffices.
+func splitDecls(s string) []string {
+	var decls []string
+	for {
+		start := strings.Index(s, "<!")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '>')
+		if end < 0 {
+			break
+		}
+		decls = append(decls, s[start+2:start+end])
+		s = s[start+end+1:]
+	}
+	return decls
+}
+
+func parseElementDecl(sc *Schema, rest string) {
+	name, spec := splitToken(rest)
+	if name == "" {
+		return
+	}
+	decl := &ElementDecl{Name: name}
+	spec = strings.TrimSpace(spec)
+
+	switch {
+	case spec == "EMPTY":
+		decl.Content.Empty = true
+	case spec == "ANY":
+		decl.Content.Any = true
+	case strings.Contains(spec, "#PCDATA"):
+		decl.Content.Mixed = true
+		decl.Content.Kind = KindChoice
+		for _, n := range leafNames(spec) {
+			if n == "#PCDATA" {
+				continue
+			}
+			decl.Content.Particles = append(decl.Content.Particles, Particle{Names: []string{n}, Occurs: Occurs{0, -1}})
+		}
+	default:
+		decl.Content.Particles, decl.Content.Kind = parseContentGroup(spec)
+	}
+	sc.Elements[name] = decl
+}
+
+func parseAttlistDecl(sc *Schema, rest string) {
+	elemName, attrSpec := splitToken(rest)
+	if elemName == "" {
+		return
+	}
+	decl, ok := sc.Elements[elemName]
+	if !ok {
+		decl = &ElementDecl{Name: elemName}
+		sc.Elements[elemName] = decl
+	}
+
+	fields := strings.Fields(attrSpec)
+	for i := 0; i+2 < len(fields); i += 3 {
+		attrName, def := fields[i], fields[i+2]
+		decl.Attrs = append(decl.Attrs, AttrDecl{Name: attrName, Required: def == "#REQUIRED"})
+	}
+}
+
+// splitToken splits off the first whitespace-delimited token.
+func splitToken(s string) (token, rest string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexAny(s, " \t\r\n")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i+1:]
+}
+
+// parseContentGroup parses a parenthesized DTD content spec like
+// "(title, author*, price?)" into its top-level particles.
+func parseContentGroup(s string) ([]Particle, Kind) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(") {
+		name, occ := nameWithSuffix(s)
+		return []Particle{{Names: []string{name}, Occurs: occ}}, KindSequence
+	}
+
+	end := matchingParen(s, 0)
+	if end < 0 {
+		return nil, KindSequence
+	}
+	items, sep := splitTopLevel(s[1:end])
+
+	kind := KindSequence
+	if sep == '|' {
+		kind = KindChoice
+	}
+
+	var particles []Particle
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.HasPrefix(item, "(") {
+			gend := matchingParen(item, 0)
+			occ := Occurs{1, 1}
+			if gend >= 0 && gend+1 < len(item) {
+				occ = occursFromSuffix(item[gend+1:])
+			}
+			if gend >= 0 {
+				if _, innerSep := splitTopLevel(item[1:gend]); innerSep == '|' {
+					// A nested choice, e.g. "(b|c)": its alternatives share
+					// one particle slot (any one of them satisfies it) and
+					// shouldn't be flattened into separate required leaves.
+					particles = append(particles, Particle{Names: leafNames(item), Occurs: occ})
+					continue
+				}
+			}
+			for _, n := range leafNames(item) {
+				particles = append(particles, Particle{Names: []string{n}, Occurs: occ})
+			}
+		} else {
+			name, occ := nameWithSuffix(item)
+			particles = append(particles, Particle{Names: []string{name}, Occurs: occ})
+		}
+	}
+	return particles, kind
+}
+
+// matchingParen returns the index of the ')' matching the '(' at open.
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s on "," or "|" at paren-depth 0 and reports which
+// separator was used (DTD content specs never mix the two at one level).
+func splitTopLevel(s string) (items []string, sep byte) {
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',', '|':
+			if depth == 0 {
+				if sep == 0 {
+					sep = s[i]
+				}
+				items = append(items, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	items = append(items, s[start:])
+	return items, sep
+}
+
+// leafNames strips parens and occurrence suffixes, returning every element
+// name mentioned anywhere inside a (possibly nested) group.
+func leafNames(s string) []string {
+	s = strings.NewReplacer("(", " ", ")", " ").Replace(s)
+	var names []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '|' || r == ' ' }) {
+		part = strings.TrimRight(part, "?*+")
+		part = strings.TrimSpace(part)
+		if part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+func nameWithSuffix(s string) (string, Occurs) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasSuffix(s, "?"):
+		return strings.TrimSuffix(s, "?"), Occurs{0, 1}
+	case strings.HasSuffix(s, "*"):
+		return strings.TrimSuffix(s, "*"), Occurs{0, -1}
+	case strings.HasSuffix(s, "+"):
+		return strings.TrimSuffix(s, "+"), Occurs{1, -1}
+	default:
+		return s, Occurs{1, 1}
+	}
+}
+
+func occursFromSuffix(suffix string) Occurs {
+	switch strings.TrimSpace(suffix) {
+	case "?":
+		return Occurs{0, 1}
+	case "*":
+		return Occurs{0, -1}
+	case "+":
+		return Occurs{1, -1}
+	default:
+		return Occurs{1, 1}
+	}
+}
@@ -0,0 +1,58 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: facets.go
+//         Checks a string value (an attribute value or an element's text)
+//         against a SimpleType's facets.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// checkFacets returns a description of the first facet value violates, or
+// "" if value satisfies every facet in t.
+func checkFacets(t *SimpleType, value string) string {
+	if t == nil {
+		return ""
+	}
+	if len(t.Enum) > 0 {
+		ok := false
+		for _, e := range t.Enum {
+			if e == value {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return fmt.Sprintf("must be one of [%s], got %q", strings.Join(t.Enum, ", "), value)
+		}
+	}
+	if t.Pattern != "" {
+		if re, err := regexp.Compile("^(?:" + t.Pattern + ")$"); err == nil && !re.MatchString(value) {
+			return fmt.Sprintf("does not match pattern %q", t.Pattern)
+		}
+	}
+	if t.MinLength >= 0 && len(value) < t.MinLength {
+		return fmt.Sprintf("is shorter than minLength %d", t.MinLength)
+	}
+	if t.MaxLength >= 0 && len(value) > t.MaxLength {
+		return fmt.Sprintf("is longer than maxLength %d", t.MaxLength)
+	}
+	if t.MinIncl != nil || t.MaxIncl != nil {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Sprintf("is not numeric, got %q", value)
+		}
+		if t.MinIncl != nil && n < *t.MinIncl {
+			return fmt.Sprintf("is less than minInclusive %v", *t.MinIncl)
+		}
+		if t.MaxIncl != nil && n > *t.MaxIncl {
+			return fmt.Sprintf("is greater than maxInclusive %v", *t.MaxIncl)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: lineindex.go
+//         Translates a byte offset (as reported by pullparser.Offset, which
+//         in turn comes from decoder.InputOffset()) into a 1-based
+//         line/column position, for diagnostics.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+// LineIndex maps byte offsets into a document to line/column positions.
+type LineIndex struct {
+	lineStarts []int64 // byte offset at which each line (0-indexed) begins
+}
+
+// NewLineIndex scans data once and builds an index for it.
+func NewLineIndex(data []byte) *LineIndex {
+	idx := &LineIndex{lineStarts: []int64{0}}
+	for i, b := range data {
+		if b == '\n' {
+			idx.lineStarts = append(idx.lineStarts, int64(i+1))
+		}
+	}
+	return idx
+}
+
+// Pos returns the 1-based line and column of offset.
+func (idx *LineIndex) Pos(offset int64) (line, col int) {
+	lo, hi := 0, len(idx.lineStarts)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if idx.lineStarts[mid] <= offset {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo + 1, int(offset-idx.lineStarts[lo]) + 1
+}
@@ -0,0 +1,32 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: loader.go
+//         Picks the DTD or XSD loader based on the schema file's extension.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadSchema opens path and compiles it into a Schema, choosing the DTD or
+// XSD loader by file extension (".dtd" or ".xsd").
+func LoadSchema(path string) (*Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dtd":
+		return LoadDTD(f)
+	case ".xsd":
+		return LoadXSD(f)
+	default:
+		return nil, fmt.Errorf("validate: unrecognized schema extension %q (want .dtd or .xsd)", filepath.Ext(path))
+	}
+}
@@ -0,0 +1,106 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: model.go
+//         The schema representation shared by the DTD and XSD loaders: a
+//         flat map of element name to its content model and attribute
+//         declarations, driven by the streaming Validator in validator.go.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+// Kind says how a ContentModel's particles relate to each other: the
+// validator enforces KindChoice as mutually exclusive (only one distinct
+// alternative may appear) and KindSequence as ordered (particles must
+// appear in declaration order), both skipped for Mixed content. KindAll's
+// only rule is cardinality. Note this is a flat, single-level model — a
+// nested group is flattened to its leaf element names (see dtd.go), so
+// ordering/exclusivity is only enforced between an element's direct
+// particles, not within a nested sub-group.
+type Kind int
+
+// The kinds of content model a ContentModel can describe.
+const (
+	KindSequence Kind = iota
+	KindChoice
+	KindAll
+)
+
+// Occurs is a min/max occurrence constraint. Max of -1 means unbounded.
+type Occurs struct {
+	Min int
+	Max int
+}
+
+// Particle is one allowed child in a content model: a child element name
+// (or, for a flattened nested group, several alternative names) together
+// with how many times it may occur.
+type Particle struct {
+	Names  []string
+	Occurs Occurs
+}
+
+// Matches reports whether name is one of this particle's alternatives.
+func (p Particle) Matches(name string) bool {
+	for _, n := range p.Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentModel describes what an element is allowed to contain.
+type ContentModel struct {
+	Kind      Kind
+	Particles []Particle
+	Mixed     bool // DTD (#PCDATA|a|b)*: text and any listed child, any order/count
+	Any       bool // DTD ANY / no declared restriction: children are not checked
+	Empty     bool // DTD EMPTY: no children or text allowed
+	TextOnly  bool // XSD simple-typed or untyped element: text checked against TextType; child elements inside it are not reported (DTD (#PCDATA) is loaded as Mixed instead)
+}
+
+// FindParticle returns the index of the particle that accepts name, if any.
+func (c ContentModel) FindParticle(name string) (int, bool) {
+	for i, p := range c.Particles {
+		if p.Matches(name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// SimpleType is the facet set applied to an attribute value or an element's
+// own text.
+type SimpleType struct {
+	Enum      []string
+	Pattern   string
+	MinLength int // -1 if unset
+	MaxLength int // -1 if unset
+	MinIncl   *float64
+	MaxIncl   *float64
+}
+
+// NewSimpleType returns a SimpleType with no facets set.
+func NewSimpleType() *SimpleType {
+	return &SimpleType{MinLength: -1, MaxLength: -1}
+}
+
+// AttrDecl is one declared attribute of an element.
+type AttrDecl struct {
+	Name     string
+	Required bool
+	Type     *SimpleType // nil if the attribute has no facets to check
+}
+
+// ElementDecl is everything known about one declared element name.
+type ElementDecl struct {
+	Name     string
+	Content  ContentModel
+	Attrs    []AttrDecl
+	TextType *SimpleType // facets on this element's own text, if it is TextOnly
+}
+
+// Schema is a flat element-name -> declaration map, the form both the DTD
+// and XSD loaders produce and Validator consumes.
+type Schema struct {
+	Elements map[string]*ElementDecl
+}
@@ -0,0 +1,235 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: validator.go
+//         Streams a document through pullparser and checks it against a
+//         Schema: validation state is a stack of "expected next child"
+//         frames kept in a container/list, one per open element, advanced
+//         on each StartTag/EndTag/Text event exactly like the element stack
+//         the original tool kept before it grew a real parser package.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+// Violation is one schema violation found during validation.
+type Violation struct {
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Message string `json:"message"`
+}
+
+// Validator checks a document stream against a Schema.
+type Validator struct {
+	schema *Schema
+	lines  *LineIndex
+}
+
+// NewValidator returns a Validator for schema, translating offsets to
+// positions via lines.
+func NewValidator(schema *Schema, lines *LineIndex) *Validator {
+	return &Validator{schema: schema, lines: lines}
+}
+
+// frame is the validation state open for one element on the stack.
+type frame struct {
+	decl       *ElementDecl
+	name       string
+	counts     map[int]int // particle index -> number of matching children seen
+	text       strings.Builder
+	seqPos     int // KindSequence: index of the particle children are currently matching
+	choiceSeen int // KindChoice: index of the alternative already chosen, or -1
+
+	// reportedMissing records particle indices checkOrder already flagged as
+	// missing (skipped over by a later sibling), so checkEnd's closing sweep
+	// doesn't report the same missing child a second time.
+	reportedMissing map[int]bool
+}
+
+func newFrame(decl *ElementDecl, name string) *frame {
+	return &frame{decl: decl, name: name, counts: map[int]int{}, choiceSeen: -1}
+}
+
+// Validate drives p to completion and returns every violation found.
+func (v *Validator) Validate(p *pullparser.XMLPullParser) ([]Violation, error) {
+	var violations []Violation
+	stack := list.New()
+
+	for {
+		ev, err := p.Next()
+		if err != nil {
+			return violations, err
+		}
+
+		switch ev {
+		case pullparser.StartTag:
+			var parent *frame
+			if stack.Len() > 0 {
+				parent = stack.Back().Value.(*frame)
+			}
+			decl := v.schema.Elements[p.Name]
+
+			if decl == nil {
+				violations = append(violations, v.violation(p, fmt.Sprintf("unexpected element <%s>: not declared in schema", p.Name)))
+			} else if parent != nil && parent.decl != nil && !parent.decl.Content.Any {
+				if idx, ok := parent.decl.Content.FindParticle(p.Name); ok {
+					violations = append(violations, v.checkOrder(p, parent, idx)...)
+					parent.counts[idx]++
+					max := parent.decl.Content.Particles[idx].Occurs.Max
+					if max != -1 && parent.counts[idx] > max {
+						violations = append(violations, v.violation(p, fmt.Sprintf("too many <%s> elements inside <%s> (max %d)", p.Name, parent.name, max)))
+					}
+				} else if !parent.decl.Content.TextOnly {
+					violations = append(violations, v.violation(p, fmt.Sprintf("<%s> is not allowed inside <%s>", p.Name, parent.name)))
+				}
+			}
+
+			if decl != nil {
+				violations = append(violations, v.checkAttrs(p, decl)...)
+			}
+			stack.PushBack(newFrame(decl, p.Name))
+
+		case pullparser.EndTag:
+			back := stack.Back()
+			f := back.Value.(*frame)
+			stack.Remove(back)
+			violations = append(violations, v.checkEnd(p, f)...)
+
+		case pullparser.Text:
+			data := strings.TrimSpace(p.Text)
+			if stack.Len() == 0 || data == "" {
+				continue
+			}
+			f := stack.Back().Value.(*frame)
+			f.text.WriteString(p.Text)
+			c := f.decl
+			if c != nil && (c.Content.Empty || (!c.Content.TextOnly && !c.Content.Mixed && len(c.Content.Particles) > 0)) {
+				violations = append(violations, v.violation(p, fmt.Sprintf("<%s> does not allow character content", f.name)))
+			}
+
+		case pullparser.EndDocument:
+			return violations, nil
+		}
+	}
+}
+
+// checkOrder enforces the relationship a content model's Kind promises
+// between its particles: KindChoice allows only one distinct alternative to
+// appear at all, and KindSequence requires particles to appear in
+// declaration order. Mixed content ((#PCDATA|a|b)*) allows any order/count
+// of its listed children, so it's exempt from both. KindAll's only rule is
+// cardinality, already enforced by the caller's occurs check.
+func (v *Validator) checkOrder(p *pullparser.XMLPullParser, parent *frame, idx int) []Violation {
+	if parent.decl.Content.Mixed {
+		return nil
+	}
+	switch parent.decl.Content.Kind {
+	case KindChoice:
+		if parent.choiceSeen == -1 {
+			parent.choiceSeen = idx
+		} else if parent.choiceSeen != idx {
+			already := parent.decl.Content.Particles[parent.choiceSeen].Names
+			return []Violation{v.violation(p, fmt.Sprintf("<%s> is not allowed inside <%s>: choice already matched <%s>", p.Name, parent.name, strings.Join(already, "|")))}
+		}
+
+	case KindSequence:
+		if idx < parent.seqPos {
+			expected := parent.decl.Content.Particles[parent.seqPos].Names
+			return []Violation{v.violation(p, fmt.Sprintf("<%s> is out of order inside <%s>: expected <%s> next", p.Name, parent.name, strings.Join(expected, "|")))}
+		}
+		var violations []Violation
+		for skip := parent.seqPos; skip < idx; skip++ {
+			if parent.counts[skip] < parent.decl.Content.Particles[skip].Occurs.Min {
+				missing := parent.decl.Content.Particles[skip].Names
+				violations = append(violations, v.violation(p, fmt.Sprintf("<%s> is missing required child <%s> before <%s>", parent.name, strings.Join(missing, "|"), p.Name)))
+				if parent.reportedMissing == nil {
+					parent.reportedMissing = map[int]bool{}
+				}
+				parent.reportedMissing[skip] = true
+			}
+		}
+		parent.seqPos = idx
+		return violations
+	}
+	return nil
+}
+
+func (v *Validator) checkAttrs(p *pullparser.XMLPullParser, decl *ElementDecl) []Violation {
+	var violations []Violation
+	for _, ad := range decl.Attrs {
+		val, present := attrValue(p.Attrs, ad.Name)
+		if ad.Required && !present {
+			violations = append(violations, v.violation(p, fmt.Sprintf("<%s> missing required attribute @%s", p.Name, ad.Name)))
+			continue
+		}
+		if present && ad.Type != nil {
+			if msg := checkFacets(ad.Type, val); msg != "" {
+				violations = append(violations, v.violation(p, fmt.Sprintf("attribute @%s of <%s> %s", ad.Name, p.Name, msg)))
+			}
+		}
+	}
+	return violations
+}
+
+func (v *Validator) checkEnd(p *pullparser.XMLPullParser, f *frame) []Violation {
+	var violations []Violation
+	if f.decl == nil {
+		return violations
+	}
+	if f.decl.TextType != nil {
+		if msg := checkFacets(f.decl.TextType, strings.TrimSpace(f.text.String())); msg != "" {
+			violations = append(violations, v.violation(p, fmt.Sprintf("<%s> text %s", f.name, msg)))
+		}
+	}
+	if !f.decl.Content.Any && !f.decl.Content.TextOnly {
+		if f.decl.Content.Kind == KindChoice && !f.decl.Content.Mixed {
+			// A choice requires exactly one of its alternatives, not each of
+			// them: report only if the choice went entirely unmatched and
+			// was actually required.
+			if f.choiceSeen == -1 && choiceRequired(f.decl.Content.Particles) {
+				var names []string
+				for _, part := range f.decl.Content.Particles {
+					names = append(names, part.Names...)
+				}
+				violations = append(violations, v.violation(p, fmt.Sprintf("<%s> is missing a required child: one of <%s>", f.name, strings.Join(names, "|"))))
+			}
+		} else {
+			for idx, part := range f.decl.Content.Particles {
+				if f.counts[idx] < part.Occurs.Min && !f.reportedMissing[idx] {
+					violations = append(violations, v.violation(p, fmt.Sprintf("<%s> is missing required child <%s>", f.name, strings.Join(part.Names, "|"))))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// choiceRequired reports whether a KindChoice group must be satisfied at
+// all, i.e. whether any alternative has a nonzero minOccurs.
+func choiceRequired(particles []Particle) bool {
+	for _, part := range particles {
+		if part.Occurs.Min > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Validator) violation(p *pullparser.XMLPullParser, msg string) Violation {
+	line, col := v.lines.Pos(p.Offset())
+	return Violation{Line: line, Col: col, Message: msg}
+}
+
+func attrValue(attrs []pullparser.Attr, name string) (string, bool) {
+	for _, a := range attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
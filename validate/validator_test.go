@@ -0,0 +1,230 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: validator_test.go
+//         Covers the content-model engine: cardinality, xs:choice
+//         exclusivity, xs:sequence/DTD group ordering, and attribute/text
+//         facets, for both the XSD and DTD loaders.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+func validateDoc(t *testing.T, schema *Schema, doc string) []Violation {
+	t.Helper()
+	data := []byte(doc)
+	v := NewValidator(schema, NewLineIndex(data))
+	violations, err := v.Validate(pullparser.New(strings.NewReader(doc)))
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	return violations
+}
+
+const choiceXSD = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="root">
+    <xs:complexType>
+      <xs:choice>
+        <xs:element name="a" maxOccurs="1"/>
+        <xs:element name="b" maxOccurs="1"/>
+      </xs:choice>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+func mustLoadXSD(t *testing.T, src string) *Schema {
+	t.Helper()
+	schema, err := LoadXSD(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("LoadXSD: %v", err)
+	}
+	return schema
+}
+
+func TestChoiceAllowsExactlyOneAlternative(t *testing.T) {
+	schema := mustLoadXSD(t, choiceXSD)
+
+	if v := validateDoc(t, schema, `<root><a/></root>`); len(v) != 0 {
+		t.Errorf("single chosen alternative: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><a/><b/></root>`); len(v) == 0 {
+		t.Errorf("both alternatives present: want a choice-exclusivity violation, got none")
+	}
+	if v := validateDoc(t, schema, `<root/>`); len(v) == 0 {
+		t.Errorf("no alternative present: want a missing-required-child violation, got none")
+	}
+}
+
+const sequenceXSD = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="root">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="a"/>
+        <xs:element name="b"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+func TestSequenceEnforcesOrder(t *testing.T) {
+	schema := mustLoadXSD(t, sequenceXSD)
+
+	if v := validateDoc(t, schema, `<root><a/><b/></root>`); len(v) != 0 {
+		t.Errorf("in-order sequence: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><b/><a/></root>`); len(v) == 0 {
+		t.Errorf("out-of-order sequence: want a violation, got none")
+	}
+}
+
+func TestCardinalityMaxOccurs(t *testing.T) {
+	const xsd = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="root">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="a" maxOccurs="1"/>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+	schema := mustLoadXSD(t, xsd)
+
+	if v := validateDoc(t, schema, `<root><a/></root>`); len(v) != 0 {
+		t.Errorf("one <a>: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><a/><a/></root>`); len(v) == 0 {
+		t.Errorf("two <a> with maxOccurs=1: want a violation, got none")
+	}
+}
+
+func TestUnexpectedAndUndeclaredElements(t *testing.T) {
+	schema := mustLoadXSD(t, sequenceXSD)
+
+	if v := validateDoc(t, schema, `<root><a/><b/><c/></root>`); len(v) == 0 {
+		t.Errorf("undeclared <c>: want a violation, got none")
+	}
+}
+
+func TestAttributeRequiredAndFacets(t *testing.T) {
+	const xsd = `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="root">
+    <xs:complexType>
+      <xs:attribute name="id" use="required"/>
+      <xs:attribute name="color">
+        <xs:simpleType>
+          <xs:restriction base="xs:string">
+            <xs:enumeration value="red"/>
+            <xs:enumeration value="blue"/>
+          </xs:restriction>
+        </xs:simpleType>
+      </xs:attribute>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+	schema := mustLoadXSD(t, xsd)
+
+	if v := validateDoc(t, schema, `<root id="1" color="red"/>`); len(v) != 0 {
+		t.Errorf("valid attrs: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root color="red"/>`); len(v) == 0 {
+		t.Errorf("missing required @id: want a violation, got none")
+	}
+	if v := validateDoc(t, schema, `<root id="1" color="green"/>`); len(v) == 0 {
+		t.Errorf("@color not in enumeration: want a violation, got none")
+	}
+}
+
+func mustLoadDTD(t *testing.T, src string) *Schema {
+	t.Helper()
+	schema, err := LoadDTD(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("LoadDTD: %v", err)
+	}
+	return schema
+}
+
+func TestDTDSequenceGroupOrderAndCardinality(t *testing.T) {
+	const dtd = `<!ELEMENT root (a,b)>
+<!ELEMENT a (#PCDATA)>
+<!ELEMENT b (#PCDATA)>`
+	schema := mustLoadDTD(t, dtd)
+
+	if v := validateDoc(t, schema, `<root><a>x</a><b>y</b></root>`); len(v) != 0 {
+		t.Errorf("in-order DTD sequence: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><b>y</b><a>x</a></root>`); len(v) == 0 {
+		t.Errorf("out-of-order DTD sequence: want a violation, got none")
+	}
+}
+
+func TestDTDSequenceNoDuplicateMissingChildViolation(t *testing.T) {
+	const dtd = `<!ELEMENT root (a,b)>
+<!ELEMENT a (#PCDATA)>
+<!ELEMENT b (#PCDATA)>`
+	schema := mustLoadDTD(t, dtd)
+
+	v := validateDoc(t, schema, `<root><b>y</b></root>`)
+	if len(v) != 1 {
+		t.Fatalf("missing <a> before <b>: got %d violations %v, want exactly 1", len(v), v)
+	}
+}
+
+func TestDTDNestedChoiceGroupIsOneSlot(t *testing.T) {
+	const dtd = `<!ELEMENT root (a,(b|c))>
+<!ELEMENT a (#PCDATA)>
+<!ELEMENT b (#PCDATA)>
+<!ELEMENT c (#PCDATA)>`
+	schema := mustLoadDTD(t, dtd)
+
+	if v := validateDoc(t, schema, `<root><a>x</a><b>y</b></root>`); len(v) != 0 {
+		t.Errorf("a then chosen alternative b: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><a>x</a><c>y</c></root>`); len(v) != 0 {
+		t.Errorf("a then chosen alternative c: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><a>x</a></root>`); len(v) == 0 {
+		t.Errorf("neither b nor c present: want a missing-child violation, got none")
+	}
+}
+
+func TestDTDChoiceGroupExclusivity(t *testing.T) {
+	const dtd = `<!ELEMENT root (a|b)>
+<!ELEMENT a (#PCDATA)>
+<!ELEMENT b (#PCDATA)>`
+	schema := mustLoadDTD(t, dtd)
+
+	if v := validateDoc(t, schema, `<root><a>x</a></root>`); len(v) != 0 {
+		t.Errorf("single DTD choice alternative: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root><a>x</a><b>y</b></root>`); len(v) == 0 {
+		t.Errorf("both DTD choice alternatives present: want a violation, got none")
+	}
+}
+
+func TestDTDMixedContentAnyOrderAnyCount(t *testing.T) {
+	const dtd = `<!ELEMENT root (#PCDATA|a|b)*>
+<!ELEMENT a (#PCDATA)>
+<!ELEMENT b (#PCDATA)>`
+	schema := mustLoadDTD(t, dtd)
+
+	if v := validateDoc(t, schema, `<root>text<b/><b/><a/></root>`); len(v) != 0 {
+		t.Errorf("mixed content, any order/count: got violations %v, want none", v)
+	}
+}
+
+func TestDTDRequiredAttribute(t *testing.T) {
+	const dtd = `<!ELEMENT root (#PCDATA)>
+<!ATTLIST root id CDATA #REQUIRED>`
+	schema := mustLoadDTD(t, dtd)
+
+	if v := validateDoc(t, schema, `<root id="1"/>`); len(v) != 0 {
+		t.Errorf("required attr present: got violations %v, want none", v)
+	}
+	if v := validateDoc(t, schema, `<root/>`); len(v) == 0 {
+		t.Errorf("required attr missing: want a violation, got none")
+	}
+}
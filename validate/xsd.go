@@ -0,0 +1,243 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: xsd.go
+//         Parses a minimal XSD 1.0 subset into a Schema: xs:element,
+//         xs:complexType (sequence/choice/all of xs:element, each with
+//         minOccurs/maxOccurs), xs:attribute (use="required"), and
+//         xs:simpleType/xs:restriction facets (enumeration, pattern,
+//         minLength, maxLength, minInclusive, maxInclusive).
+//
+//         Rather than writing a second XML walker, this reads the schema
+//         (itself well-formed XML) with the same encode.Build used for the
+//         tool's JSON/YAML output, then interprets the resulting generic
+//         tree — xs: namespace prefixes are irrelevant since pullparser
+//         already reduces every element/attribute to its local name.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package validate
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jschwartzman/xmlParse/encode"
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+// LoadXSD reads an XSD document from r and compiles it into a Schema.
+func LoadXSD(r io.Reader) (*Schema, error) {
+	root, value, err := encode.Build(pullparser.New(r), encode.DefaultOptions())
+	if err != nil {
+		return nil, err
+	}
+	if root != "schema" {
+		return nil, fmt.Errorf("validate: expected an xs:schema root, found <%s>", root)
+	}
+	schemaNode, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("validate: empty schema")
+	}
+
+	b := &xsdBuilder{
+		schema:       &Schema{Elements: map[string]*ElementDecl{}},
+		complexTypes: map[string]map[string]interface{}{},
+		simpleTypes:  map[string]map[string]interface{}{},
+	}
+	for _, m := range asMaps(schemaNode["complexType"]) {
+		if name, ok := attrString(m, "name"); ok {
+			b.complexTypes[name] = m
+		}
+	}
+	for _, m := range asMaps(schemaNode["simpleType"]) {
+		if name, ok := attrString(m, "name"); ok {
+			b.simpleTypes[name] = m
+		}
+	}
+	for _, m := range asMaps(schemaNode["element"]) {
+		b.element(m)
+	}
+	return b.schema, nil
+}
+
+type xsdBuilder struct {
+	schema       *Schema
+	complexTypes map[string]map[string]interface{}
+	simpleTypes  map[string]map[string]interface{}
+}
+
+// element registers the ElementDecl for one xs:element entry (top-level or
+// nested inside a group) into the schema's flat name map.
+func (b *xsdBuilder) element(m map[string]interface{}) {
+	name, ok := attrString(m, "name")
+	if !ok {
+		return
+	}
+	if _, already := b.schema.Elements[name]; already {
+		return
+	}
+	decl := &ElementDecl{Name: name}
+	b.schema.Elements[name] = decl // insert before recursing, in case of self-reference
+
+	switch {
+	case attrStringOr(m, "type", "") != "":
+		typeName, _ := attrString(m, "type")
+		if ct, ok := b.complexTypes[typeName]; ok {
+			decl.Content, decl.Attrs = b.complexType(ct)
+		} else if st, ok := b.simpleTypes[typeName]; ok {
+			decl.Content.TextOnly = true
+			decl.TextType = b.simpleType(st)
+		} else {
+			decl.Content.TextOnly = true // built-in type (xs:string, xs:int, ...): text, no facets
+		}
+	case len(asMaps(m["complexType"])) > 0:
+		decl.Content, decl.Attrs = b.complexType(asMaps(m["complexType"])[0])
+	case len(asMaps(m["simpleType"])) > 0:
+		decl.Content.TextOnly = true
+		decl.TextType = b.simpleType(asMaps(m["simpleType"])[0])
+	default:
+		decl.Content.TextOnly = true // no type info: treat leniently as free text
+	}
+}
+
+func (b *xsdBuilder) complexType(ct map[string]interface{}) (ContentModel, []AttrDecl) {
+	var cm ContentModel
+	for key, kind := range map[string]Kind{"sequence": KindSequence, "choice": KindChoice, "all": KindAll} {
+		groups := asMaps(ct[key])
+		if len(groups) == 0 {
+			continue
+		}
+		cm.Kind = kind
+		for _, em := range asMaps(groups[0]["element"]) {
+			name, ok := attrString(em, "name")
+			if !ok {
+				continue
+			}
+			cm.Particles = append(cm.Particles, Particle{Names: []string{name}, Occurs: occursOf(em)})
+			b.element(em) // register its own declaration (supports inline anonymous types)
+		}
+	}
+
+	var attrs []AttrDecl
+	for _, am := range asMaps(ct["attribute"]) {
+		name, ok := attrString(am, "name")
+		if !ok {
+			continue
+		}
+		use, _ := attrString(am, "use")
+		ad := AttrDecl{Name: name, Required: use == "required"}
+		if st := asMaps(am["simpleType"]); len(st) > 0 {
+			ad.Type = b.simpleType(st[0])
+		}
+		attrs = append(attrs, ad)
+	}
+	return cm, attrs
+}
+
+func (b *xsdBuilder) simpleType(st map[string]interface{}) *SimpleType {
+	t := NewSimpleType()
+	restr := asMaps(st["restriction"])
+	if len(restr) == 0 {
+		return t
+	}
+	r := restr[0]
+	for _, e := range asMaps(r["enumeration"]) {
+		if v, ok := attrString(e, "value"); ok {
+			t.Enum = append(t.Enum, v)
+		}
+	}
+	if p := asMaps(r["pattern"]); len(p) > 0 {
+		if v, ok := attrString(p[0], "value"); ok {
+			t.Pattern = v
+		}
+	}
+	if n := asMaps(r["minLength"]); len(n) > 0 {
+		t.MinLength = intAttr(n[0], "value", -1)
+	}
+	if n := asMaps(r["maxLength"]); len(n) > 0 {
+		t.MaxLength = intAttr(n[0], "value", -1)
+	}
+	if n := asMaps(r["minInclusive"]); len(n) > 0 {
+		if f, ok := floatAttr(n[0], "value"); ok {
+			t.MinIncl = &f
+		}
+	}
+	if n := asMaps(r["maxInclusive"]); len(n) > 0 {
+		if f, ok := floatAttr(n[0], "value"); ok {
+			t.MaxIncl = &f
+		}
+	}
+	return t
+}
+
+func occursOf(em map[string]interface{}) Occurs {
+	min, max := 1, 1
+	if v, ok := attrString(em, "minOccurs"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			min = n
+		}
+	}
+	if v, ok := attrString(em, "maxOccurs"); ok {
+		if v == "unbounded" {
+			max = -1
+		} else if n, err := strconv.Atoi(v); err == nil {
+			max = n
+		}
+	}
+	return Occurs{min, max}
+}
+
+// asMaps normalizes an encode.Build field, which is nil, a single
+// map[string]interface{}, or a []interface{} of maps depending on whether
+// the element repeated, into a slice of maps.
+func asMaps(v interface{}) []map[string]interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return []map[string]interface{}{t}
+	case []interface{}:
+		out := make([]map[string]interface{}, 0, len(t))
+		for _, item := range t {
+			if m, ok := item.(map[string]interface{}); ok {
+				out = append(out, m)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func attrString(m map[string]interface{}, attr string) (string, bool) {
+	v, ok := m["+"+attr]
+	if !ok {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
+
+func attrStringOr(m map[string]interface{}, attr, fallback string) string {
+	if s, ok := attrString(m, attr); ok {
+		return s
+	}
+	return fallback
+}
+
+func intAttr(m map[string]interface{}, attr string, fallback int) int {
+	if s, ok := attrString(m, attr); ok {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func floatAttr(m map[string]interface{}, attr string) (float64, bool) {
+	s, ok := attrString(m, attr)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	return n, err == nil
+}
@@ -0,0 +1,60 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: validate_cmd.go
+//         The --validate code path: loads a DTD or XSD schema (picked by
+//         the schema file's extension) once up front, then streams each
+//         document through the validate package, reporting every violation
+//         found. "problems" in the Renderer sense is the violation count.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+	"github.com/jschwartzman/xmlParse/validate"
+)
+
+// validateRenderer is the Renderer for --validate. The schema is loaded once
+// and shared read-only across every concurrently validated file.
+type validateRenderer struct {
+	schema     *validate.Schema
+	reportJSON bool
+}
+
+// newValidateRenderer loads schemaPath and returns a Renderer that checks
+// every file it's given against it.
+func newValidateRenderer(schemaPath string, reportJSON bool) (*validateRenderer, error) {
+	schema, err := validate.LoadSchema(schemaPath)
+	if err != nil {
+		return nil, err
+	}
+	return &validateRenderer{schema: schema, reportJSON: reportJSON}, nil
+}
+
+func (r *validateRenderer) Render(xmlFile io.Reader, w io.Writer) (int, error) {
+	data, err := io.ReadAll(xmlFile)
+	if err != nil {
+		return 0, fmt.Errorf("reading document: %w", err)
+	}
+
+	v := validate.NewValidator(r.schema, validate.NewLineIndex(data))
+	violations, err := v.Validate(pullparser.New(bytes.NewReader(data)))
+	if err != nil {
+		return 0, err
+	}
+
+	if r.reportJSON {
+		out, _ := json.MarshalIndent(violations, "", "  ")
+		fmt.Fprintln(w, string(out))
+	} else {
+		for _, v := range violations {
+			fmt.Fprintf(w, "%d:%d: %s\n", v.Line, v.Col, v.Message)
+		}
+		fmt.Fprintf(w, "\n%d violation(s)\n", len(violations))
+	}
+	return len(violations), nil
+}
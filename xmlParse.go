@@ -3,17 +3,20 @@
 //         A Go SAX utility that parses and displays
 //         well-formed xml files.
 // author: John Schwartzman, Forte Systems, Inc.
-// VERSION_NUMBER = "0.1.0"
-// last revision:	03/13/2019
+// VERSION_NUMBER = "0.5.0"
+// last revision:	07/26/2026
 //////////////////////////////////////////////////////////////////////////////
 package main
 
 import (
-	"container/list"
-	"encoding/xml"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
 	"strings"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
 )
 
 // define some constant color escape sequences
@@ -36,157 +39,211 @@ const comment = yellow + "%s" + white
 const elementdata = green + "%s " + white
 const directivedata = cyan + "%s\n" + white
 
-var nLastWritePos = 0 // must be defined before use
 var bShowComments = true
-var nFileArg = 1
 
-func writeNewLine() { // advance the cursor row
-	fmt.Printf("\n")
+// treeWriter holds the colored-tree printer's per-render state (its current
+// indentation column). It used to live in package-level vars, but once jobs
+// started running concurrently a shared nLastWritePos would tear between
+// files, so each render now gets its own treeWriter over its own io.Writer.
+type treeWriter struct {
+	w            io.Writer
+	lastWritePos int
+}
+
+func newTreeWriter(w io.Writer) *treeWriter {
+	return &treeWriter{w: w}
 }
 
-func writeSpaces(pos int, chars string) { // position the cursor column
+func (tw *treeWriter) newLine() { // advance the cursor row
+	fmt.Fprintf(tw.w, "\n")
+}
+
+func (tw *treeWriter) spacing(pos int, chars string) { // position the cursor column
 	for i := 0; i < pos; i++ {
-		fmt.Printf(spaces, chars)
+		fmt.Fprintf(tw.w, spaces, chars)
 	}
 }
 
-func writeComment(data string) { // write data at current x,y
-	fmt.Printf(comment, data)
+func (tw *treeWriter) comment(data string) { // write data at current x,y
+	fmt.Fprintf(tw.w, comment, data)
 }
 
-func writeStartName(name string) { // write startName at current x,y
-	fmt.Printf(startname, name)
+func (tw *treeWriter) startName(name string) { // write startName at current x,y
+	fmt.Fprintf(tw.w, startname, name)
 }
 
-func writeEndName(name string) { // write endName at current x,y
-	fmt.Printf(endname, name)
+func (tw *treeWriter) endName(name string) { // write endName at current x,y
+	fmt.Fprintf(tw.w, endname, name)
 }
 
-func writeCharacterData(data string) { // write data at current x,y
-	fmt.Printf(elementdata, data)
+func (tw *treeWriter) characterData(data string) { // write data at current x,y
+	fmt.Fprintf(tw.w, elementdata, data)
 }
 
-func writeElementData(data string) { // write data at current x,y
-	fmt.Printf(elementdata, data)
+func (tw *treeWriter) elementData(data string) { // write data at current x,y
+	fmt.Fprintf(tw.w, elementdata, data)
 }
 
-func writeDirective(data string) { // write directive at current x,y
-	fmt.Printf(directivedata, data)
+func (tw *treeWriter) directive(data string) { // write directive at current x,y
+	fmt.Fprintf(tw.w, directivedata, data)
 }
 
-func writeAttribute(attrName string, attrValue string) { // write attribute
-	fmt.Printf(parenstmnt, attrName, attrValue) // write name-value pair
+func (tw *treeWriter) attribute(attrName string, attrValue string) { // write attribute
+	fmt.Fprintf(tw.w, parenstmnt, attrName, attrValue) // write name-value pair
 }
 
-// add an element to the end of the list (top of the stack)
-func push(s *list.List, name string) int {
-	pos := s.Len()   // use the index before push
-	s.PushBack(name) // push it onto the stack
-	return pos
-}
+// stringList collects repeated occurrences of a flag, e.g. multiple -q exprs.
+type stringList []string
 
-// remove the element at the end of the list (top of the stack)
-func pop(s *list.List, name string) int {
-	e := s.Back() // get the last element in the list
-	if e.Value == name {
-		s.Remove(e) //pop it from the stack
-	} else {
-		fmt.Printf("%s\nError: %s was not at the top of the stack.\n\n",
-			white, name)
-		os.Exit(4)
-	}
-	return s.Len() // use the index after pop
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
 }
 
 func usage(exitCode int) {
-	fmt.Printf("\nUSAGE: xmlParse [-h || --help]||[-i || --ignore_comments] xmlFile\n\n")
+	fmt.Printf("\nUSAGE: xmlParse [-i || --ignore_comments] [-q EXPR || --query EXPR]... [--count]\n")
+	fmt.Printf("                [-o json|yaml|xml] [--from json|yaml] [--attr-prefix P] [--content-key K]\n")
+	fmt.Printf("                [--validate schema.dtd|schema.xsd] [--report json]\n")
+	fmt.Printf("                [--jobs N] [--keep-going] file...|glob...|-\n\n")
+	flag.PrintDefaults()
+	fmt.Printf("\n")
 	os.Exit(exitCode)
 }
 
 func main() {
-	if len(os.Args) < 2 || len(os.Args) > 3 { // there must be 2 or 3 arguments
+	var help bool
+	var ignoreComments bool
+	var queries stringList
+	var countOnly bool
+	var outFormat string
+	var fromFormat string
+	var attrPrefix string
+	var contentKey string
+	var validateSchema string
+	var report string
+	var jobs int
+	var keepGoing bool
+
+	flag.BoolVar(&help, "h", false, "show usage")
+	flag.BoolVar(&help, "help", false, "show usage")
+	flag.BoolVar(&ignoreComments, "i", false, "don't print comments")
+	flag.BoolVar(&ignoreComments, "ignore_comments", false, "don't print comments")
+	flag.Var(&queries, "q", "XPath expression to select nodes instead of dumping the whole tree (repeatable, OR'd together)")
+	flag.Var(&queries, "query", "same as -q")
+	flag.BoolVar(&countOnly, "count", false, "with -q, print only the number of matches")
+	flag.StringVar(&outFormat, "o", "", "output format: json, yaml, or xml (default: the colored tree)")
+	flag.StringVar(&fromFormat, "from", "", "read the input file as this structured format (json or yaml) instead of XML")
+	flag.StringVar(&attrPrefix, "attr-prefix", "+", "prefix for attribute keys in json/yaml output")
+	flag.StringVar(&contentKey, "content-key", "#text", "key holding an element's own text in json/yaml output")
+	flag.StringVar(&validateSchema, "validate", "", "validate the document against a .dtd or .xsd schema instead of printing it (choice/sequence/cardinality are enforced one level deep; nested groups are flattened)")
+	flag.StringVar(&report, "report", "", "with --validate, use 'json' for machine-readable diagnostics")
+	flag.IntVar(&jobs, "jobs", runtime.NumCPU(), "number of files to parse concurrently")
+	flag.BoolVar(&keepGoing, "keep-going", false, "keep processing remaining files after one fails")
+	flag.Usage = func() { usage(0) }
+	flag.Parse()
+
+	if help {
+		usage(0)
+	}
+	if flag.NArg() < 1 {
 		fmt.Printf("\nYou have provided an incorrect number of arguments.\n")
 		usage(1)
 	}
-	if os.Args[1] == "-h" || os.Args[1] == "--help" { // want help?
-		usage(0)
-	}
-	if len(os.Args) != 3 && (os.Args[1] == "-i" || os.Args[1] == "--ignore_comments") {
-		fmt.Printf("\nYou didn't provide the name of the xml file you want to parse.\n")
-		usage(2)
+	bShowComments = !ignoreComments
+
+	paths, err := expandPaths(flag.Args())
+	if err != nil {
+		fmt.Printf("\nError: %s\n\n", err)
+		os.Exit(2)
 	}
 
-	optStr := os.Args[1] // we're not using an option parser so check manually
-	if strings.HasPrefix(optStr, "-") {
-		if optStr != "-h" && optStr != "--help" && optStr != "-i" && optStr != "--ignore_comments" {
-			fmt.Printf("\nYou have entered an unknown option.\n")
-			usage(3)
+	var renderer Renderer
+	switch {
+	case validateSchema != "":
+		renderer, err = newValidateRenderer(validateSchema, report == "json")
+		if err != nil {
+			fmt.Printf("\nError: %s\n\n", err)
+			os.Exit(2)
 		}
+
+	case fromFormat != "" || outFormat != "":
+		renderer = &convertRenderer{from: fromFormat, to: outFormat, opts: encodeOptions(attrPrefix, contentKey)}
+
+	case len(queries) > 0:
+		renderer = &queryRenderer{exprs: queries, countOnly: countOnly}
+
+	default:
+		renderer = treeRenderer{}
 	}
-	if os.Args[1] == "-i" || os.Args[1] == "--ignore_comments" { // want comments?
-		bShowComments = false
-		nFileArg = 2
-	}
-	xmlFile, e := os.Open(os.Args[nFileArg]) // os.Args[1 or 2] is xml file
-	if e != nil {
-		fmt.Printf("\nProblem reading %s: %s\n\n", os.Args[1], e)
-		os.Exit(2)
-	}
-	decoder := xml.NewDecoder(xmlFile) // create and initializethe decoder
-	elementStack := list.New()         // create the stack
-
-	for { // while there are tokens, stay in for loop
-		// get a new token
-		t, err := decoder.Token()
-		if err != nil && err.Error() != "EOF" {
-			fmt.Printf("Error: %s\n", err)
-			os.Exit(3)
+
+	os.Exit(runJobs(paths, renderer, jobs, keepGoing))
+}
+
+// printTree walks the whole document with the pull parser and writes every
+// node in order, the original (and default) behavior of this tool.
+func printTree(xmlFile io.Reader, w io.Writer) error {
+	parser := pullparser.New(xmlFile) // the pull parser drives the token stream
+	tw := newTreeWriter(w)
+
+	for { // while there are events, stay in for loop
+		ev, err := parser.Next() // get the next event
+		if err != nil {
+			return err
 		}
-		if t == nil {
+		if ev == pullparser.EndDocument {
 			// we've reached the end of the document
 			break // exit the for loop
 		}
 
-		// Inspect the type of the token
-		switch se := t.(type) {
+		// Inspect the type of the event
+		switch ev {
 
-		case xml.StartElement: // we've encountered a startElement
-			pos := push(elementStack, se.Name.Local) // push it onto the stack
-			writeNewLine()
-			writeSpaces(pos, "   ") // write 3 spaces per index position
-			writeStartName(se.Name.Local)
-			for _, a := range se.Attr { // don't need index so use dummy var
-				writeAttribute(a.Name.Local, a.Value)
+		case pullparser.StartTag: // we've encountered a startElement
+			pos := parser.Depth - 1 // 0-based nesting level of this element
+			tw.newLine()
+			tw.spacing(pos, "   ") // write 3 spaces per index position
+			tw.startName(parser.Name)
+			for _, a := range parser.Attrs { // don't need index so use dummy var
+				tw.attribute(a.Name, a.Value)
 			}
-			nLastWritePos = pos
+			tw.lastWritePos = pos
 
-		case xml.EndElement: // we've encountered an endElement
-			pos := pop(elementStack, se.Name.Local) // pop it from the stack
-			if pos < nLastWritePos {                // write name at current x pos?
-				writeSpaces(pos, "   ") // set x position to write end element
+		case pullparser.EndTag: // we've encountered an endElement
+			pos := parser.Depth        // 0-based nesting level of this element
+			if pos < tw.lastWritePos { // write name at current x pos?
+				tw.spacing(pos, "   ") // set x position to write end element
 			}
-			writeEndName(se.Name.Local)
+			tw.endName(parser.Name)
 
-		case xml.CharData: // we've encountered element data
+		case pullparser.Text: // we've encountered element data
 			// remove any surronding whitespace
-			data := strings.TrimSpace(string(t.(xml.CharData)))
+			data := strings.TrimSpace(parser.Text)
 			if data != "" {
-				writeCharacterData(data) // write it at current x,y
+				tw.characterData(data) // write it at current x,y
 			}
 
-		case xml.Comment: // we've encountered a comment
+		case pullparser.Comment: // we've encountered a comment
 			if bShowComments {
-				data := string(t.(xml.Comment)) // write it at current x,y
-				writeComment(data)
+				tw.comment(parser.Text) // write it at current x,y
 			}
 
-		case xml.Directive: // we've encountered a directive
-			data := string(t.(xml.Directive))
-			writeDirective(data)
+		case pullparser.Directive: // we've encountered a directive
+			tw.directive(parser.Text)
 
 		} // end of switch statement
 	} // end of for loop
 
-	fmt.Printf(white) // restore normal screen formatting
-	writeNewLine()
+	fmt.Fprintf(w, white) // restore normal screen formatting
+	tw.newLine()
+	return nil
+}
+
+// treeRenderer is the Renderer for the tool's original (and default)
+// behavior: the full colored tree.
+type treeRenderer struct{}
+
+func (treeRenderer) Render(xmlFile io.Reader, w io.Writer) (int, error) {
+	return 0, printTree(xmlFile, w)
 }
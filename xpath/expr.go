@@ -0,0 +1,300 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: expr.go
+//         Compiles a minimal XPath 1.0 subset (child/descendant axes,
+//         wildcards, attribute and position predicates, last(), text(),
+//         name(), contains() and starts-with()) into a Expr the streaming
+//         Matcher in matcher.go can evaluate one step at a time.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package xpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pred is one bracketed predicate on a Step. Only the fields relevant to
+// the predicate's kind are set.
+type Pred struct {
+	Attr     string // attribute name tested by [@attr] / [@attr='v'], "" if unused
+	HasValue bool   // true if Value should be compared, false for plain [@attr] existence
+	Value    string
+
+	Pos  int  // 1-based position test [n], 0 if unused
+	Last bool // true for [last()]
+
+	Func     string // "contains" or "starts-with", "" if unused
+	FuncAttr string // attribute name the function reads, "" to read text()/name()
+	FuncName bool   // true if the function reads name() rather than text()
+	FuncStr  string // literal argument compared against
+}
+
+// Step is one location-path step, e.g. "a", "*", "@id" or "text()".
+type Step struct {
+	Descendant bool   // reached via "//" (descendant axis) rather than "/" (child axis)
+	Name       string // element local name, or "*" for any element
+	Attr       string // non-empty when this step selects an attribute, e.g. "@id" -> "id"
+	Text       bool   // true when this step selects text() nodes instead of an element
+	Preds      []Pred
+}
+
+// Expr is a single compiled "/a/b[...]" or "//c" path expression.
+type Expr struct {
+	Raw   string
+	Steps []Step
+}
+
+// Compile parses exprs (one XPath string per -q flag) into a slice of
+// compiled Exprs. A document node matches the query if it matches ANY of
+// the returned Exprs (OR-composition of multiple -q flags).
+func Compile(exprs []string) ([]*Expr, error) {
+	compiled := make([]*Expr, 0, len(exprs))
+	for _, raw := range exprs {
+		e, err := compileOne(raw)
+		if err != nil {
+			return nil, fmt.Errorf("xpath: %q: %w", raw, err)
+		}
+		compiled = append(compiled, e)
+	}
+	return compiled, nil
+}
+
+func compileOne(raw string) (*Expr, error) {
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	descendant := false
+	if strings.HasPrefix(s, "//") {
+		descendant = true
+		s = s[2:]
+	} else if strings.HasPrefix(s, "/") {
+		s = s[1:]
+	}
+
+	e := &Expr{Raw: raw}
+	for _, tok := range splitSteps(s) {
+		step, err := parseStep(tok.text, descendant || tok.descendant)
+		if err != nil {
+			return nil, err
+		}
+		e.Steps = append(e.Steps, step)
+		descendant = false
+	}
+	if len(e.Steps) == 0 {
+		return nil, fmt.Errorf("no steps in expression")
+	}
+	return e, nil
+}
+
+type rawStep struct {
+	descendant bool
+	text       string
+}
+
+// splitSteps breaks a path into its "/"-separated steps, tracking which
+// steps were introduced by "//" (descendant axis), without being confused
+// by "/" appearing inside a predicate's string literal or brackets.
+func splitSteps(s string) []rawStep {
+	var steps []rawStep
+	descendant := false
+	depth := 0
+	inQuote := byte(0)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			steps = append(steps, rawStep{descendant: descendant, text: s[start:end]})
+		}
+		descendant = false
+	}
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			}
+		case c == '\'' || c == '"':
+			inQuote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '/' && depth == 0:
+			flush(i)
+			if i+1 < len(s) && s[i+1] == '/' {
+				descendant = true
+				i++
+			}
+			start = i + 1
+		}
+		i++
+	}
+	flush(len(s))
+	return steps
+}
+
+func parseStep(tok string, descendant bool) (Step, error) {
+	step := Step{Descendant: descendant}
+
+	name := tok
+	if br := strings.IndexByte(tok, '['); br >= 0 {
+		name = tok[:br]
+		preds, err := parsePreds(tok[br:])
+		if err != nil {
+			return step, err
+		}
+		step.Preds = preds
+	}
+
+	switch {
+	case name == "text()":
+		step.Text = true
+	case strings.HasPrefix(name, "@"):
+		step.Attr = name[1:]
+	case name == "" || name == "*" || isNameToken(name):
+		if name == "" {
+			name = "*"
+		}
+		step.Name = name
+	default:
+		return step, fmt.Errorf("invalid step %q", tok)
+	}
+	return step, nil
+}
+
+func isNameToken(s string) bool {
+	for i, r := range s {
+		if r == '_' || r == '-' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			continue
+		}
+		if i > 0 && r >= '0' && r <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// parsePreds parses one or more consecutive "[...]" groups.
+func parsePreds(s string) ([]Pred, error) {
+	var preds []Pred
+	for len(s) > 0 {
+		if s[0] != '[' {
+			return nil, fmt.Errorf("expected '[' in %q", s)
+		}
+		depth := 0
+		end := -1
+		for i := 0; i < len(s); i++ {
+			switch s[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					end = i
+				}
+			}
+			if end >= 0 {
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated predicate in %q", s)
+		}
+		pred, err := parsePred(s[1:end])
+		if err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+		s = s[end+1:]
+	}
+	return preds, nil
+}
+
+func parsePred(body string) (Pred, error) {
+	body = strings.TrimSpace(body)
+	switch {
+	case body == "last()":
+		return Pred{Last: true}, nil
+
+	case isPositiveInt(body):
+		n, _ := strconv.Atoi(body)
+		return Pred{Pos: n}, nil
+
+	case strings.HasPrefix(body, "@"):
+		rest := body[1:]
+		if eq := strings.IndexByte(rest, '='); eq >= 0 {
+			return Pred{Attr: rest[:eq], HasValue: true, Value: unquote(rest[eq+1:])}, nil
+		}
+		return Pred{Attr: rest}, nil
+
+	case strings.HasPrefix(body, "contains(") || strings.HasPrefix(body, "starts-with("):
+		fn := "contains"
+		inner := strings.TrimPrefix(body, "contains(")
+		if strings.HasPrefix(body, "starts-with(") {
+			fn = "starts-with"
+			inner = strings.TrimPrefix(body, "starts-with(")
+		}
+		inner = strings.TrimSuffix(inner, ")")
+		arg, lit, err := splitFuncArgs(inner)
+		if err != nil {
+			return Pred{}, err
+		}
+		p := Pred{Func: fn, FuncStr: unquote(lit)}
+		switch {
+		case arg == "text()":
+			// reads the node's text by default
+		case arg == "name()":
+			p.FuncName = true
+		case strings.HasPrefix(arg, "@"):
+			p.FuncAttr = arg[1:]
+		default:
+			return Pred{}, fmt.Errorf("unsupported argument %q to %s()", arg, fn)
+		}
+		return p, nil
+	}
+	return Pred{}, fmt.Errorf("unsupported predicate [%s]", body)
+}
+
+func splitFuncArgs(s string) (arg, lit string, err error) {
+	inQuote := byte(0)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case inQuote != 0:
+			if s[i] == inQuote {
+				inQuote = 0
+			}
+		case s[i] == '\'' || s[i] == '"':
+			inQuote = s[i]
+		case s[i] == ',':
+			return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), nil
+		}
+	}
+	return "", "", fmt.Errorf("expected two arguments in %q", s)
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 {
+		if (s[0] == '\'' && s[len(s)-1] == '\'') || (s[0] == '"' && s[len(s)-1] == '"') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+func isPositiveInt(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,313 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: matcher.go
+//         Streaming evaluator for compiled Exprs. Compiles down to a small
+//         NFA of step-matchers keyed by element depth: active states are
+//         pushed/popped as pullparser.StartTag/EndTag events fire, and a
+//         subtree is only buffered once a terminal state is reached, so
+//         large documents can be queried without materializing them.
+//
+//         Scope: [@attr] and [n] predicates are checked at every step, since
+//         both can be decided the moment a StartTag is seen. last() and the
+//         text()/name() functions are only evaluated on an expression's
+//         final step (the node actually being selected) — supporting them
+//         mid-path would require buffering arbitrarily deep subtrees before
+//         the path could even be confirmed, defeating the point of
+//         streaming.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package xpath
+
+import (
+	"strings"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+// Kind identifies what a Node represents.
+type Kind int
+
+// The kinds of node a Matcher can emit.
+const (
+	ElementNode Kind = iota
+	AttrNode
+	TextNode
+)
+
+// Node is a matched result: either a captured element subtree, a single
+// attribute, or a single text node, depending on Kind.
+type Node struct {
+	Kind     Kind
+	Name     string
+	Attrs    []pullparser.Attr
+	Text     string
+	Children []*Node
+}
+
+// runState is one partially matched instance of an Expr: it is waiting for
+// a child at Anchor+1 (child axis) or any deeper descendant (descendant
+// axis) to satisfy Expr.Steps[Step].
+type runState struct {
+	expr   *Expr
+	step   int
+	anchor int
+}
+
+type pendingLast struct {
+	node *Node
+	pos  int
+}
+
+type frame struct {
+	capture  bool      // true once inside a matched subtree being materialized
+	node     *Node     // the Node under construction, valid when capture is true
+	terminal *runState // set when this frame IS the terminal match of expr.Steps[terminal.step]
+
+	states    []*runState                 // continuations waiting for a child of this element
+	counts    map[*runState]int           // position() counters, keyed by the waiting state
+	pending   map[*runState][]pendingLast // candidates deferred for last(), by waiting state
+	selfAttrs []*Node                     // @attr/text() matches resolved against this element itself
+}
+
+// Matcher streams pullparser events and reports the nodes selected by a set
+// of compiled Exprs.
+type Matcher struct {
+	exprs []*Expr
+}
+
+// NewMatcher returns a Matcher evaluating the union of exprs.
+func NewMatcher(exprs []*Expr) *Matcher {
+	return &Matcher{exprs: exprs}
+}
+
+// Eval drives p to completion, calling emit for every matched Node, and
+// returns the total number of matches.
+func (m *Matcher) Eval(p *pullparser.XMLPullParser, emit func(*Node)) (int, error) {
+	count := 0
+	root := &frame{states: m.initialStates()}
+	stack := []*frame{root}
+
+	report := func(n *Node) {
+		count++
+		emit(n)
+	}
+
+	for {
+		ev, err := p.Next()
+		if err != nil {
+			return count, err
+		}
+
+		top := stack[len(stack)-1]
+
+		switch ev {
+		case pullparser.StartTag:
+			stack = append(stack, m.enter(top, p.Depth, p.Name, p.Attrs))
+
+		case pullparser.EndTag:
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			m.leave(stack[len(stack)-1], f, report)
+
+		case pullparser.Text:
+			data := strings.TrimSpace(p.Text)
+			if data == "" {
+				continue
+			}
+			if top.capture {
+				top.node.Text += data
+			}
+			for _, st := range top.states {
+				step := st.expr.Steps[st.step]
+				if step.Text && st.step == len(st.expr.Steps)-1 {
+					report(&Node{Kind: TextNode, Name: "text()", Text: data})
+				}
+			}
+
+		case pullparser.EndDocument:
+			resolvePending(root, report)
+			return count, nil
+		}
+	}
+}
+
+func (m *Matcher) initialStates() []*runState {
+	states := make([]*runState, 0, len(m.exprs))
+	for _, e := range m.exprs {
+		states = append(states, &runState{expr: e, step: 0, anchor: 0})
+	}
+	return states
+}
+
+// enter processes a StartTag seen while parent is the currently open frame,
+// and returns the new frame pushed for the element that just started.
+func (m *Matcher) enter(parent *frame, depth int, name string, attrs []pullparser.Attr) *frame {
+	if parent.capture {
+		child := &frame{capture: true, node: &Node{Kind: ElementNode, Name: name, Attrs: attrs}}
+		return child
+	}
+
+	next := &frame{}
+	if parent.counts == nil {
+		parent.counts = map[*runState]int{}
+	}
+
+	for _, st := range parent.states {
+		step := st.expr.Steps[st.step]
+		axisOK := step.Descendant || depth == st.anchor+1
+		if !axisOK {
+			continue // child axis requires an immediate child; this state is dead here
+		}
+
+		if nameMatches(step, name) {
+			parent.counts[st]++
+			pos := parent.counts[st]
+			last := st.step == len(st.expr.Steps)-1
+			if last {
+				if immediateOK(step.Preds, attrs, pos) {
+					next.capture = true
+					next.node = &Node{Kind: ElementNode, Name: name, Attrs: attrs}
+					next.terminal = st
+				}
+			} else if immediateOK(step.Preds, attrs, pos) {
+				nextStep := st.expr.Steps[st.step+1]
+				if nextStep.Attr != "" && st.step+1 == len(st.expr.Steps)-1 {
+					// an @attr step selects an attribute of the current
+					// element itself, not of some deeper child.
+					if v, ok := attrValue(attrs, nextStep.Attr); ok {
+						next.selfAttrs = append(next.selfAttrs, &Node{Kind: AttrNode, Name: nextStep.Attr, Text: v})
+					}
+				} else {
+					// text() steps are resolved against CharData seen
+					// directly under this element (same depth), so the
+					// continuation is anchored here too.
+					next.states = append(next.states, &runState{expr: st.expr, step: st.step + 1, anchor: depth})
+				}
+			}
+		}
+		if step.Descendant {
+			next.states = append(next.states, st) // stays alive to match deeper descendants
+		}
+	}
+	return next
+}
+
+// leave finalizes the frame for an element that just ended, folding it into
+// parent (appending to a captured ancestor, resolving last() groups, or
+// emitting a terminal match).
+func (m *Matcher) leave(parent *frame, f *frame, report func(*Node)) {
+	for _, a := range f.selfAttrs {
+		report(a)
+	}
+	resolvePending(f, report)
+
+	switch {
+	case f.terminal != nil:
+		step := f.terminal.expr.Steps[f.terminal.step]
+		if !deferredOK(step.Preds, f.node) {
+			return
+		}
+		if hasLast(step.Preds) {
+			if parent.pending == nil {
+				parent.pending = map[*runState][]pendingLast{}
+			}
+			pos := parent.counts[f.terminal]
+			parent.pending[f.terminal] = append(parent.pending[f.terminal], pendingLast{node: f.node, pos: pos})
+			return
+		}
+		report(f.node)
+
+	case f.capture && parent.capture:
+		parent.node.Children = append(parent.node.Children, f.node)
+	}
+}
+
+func resolvePending(f *frame, report func(*Node)) {
+	for _, group := range f.pending {
+		best := group[0]
+		for _, cand := range group[1:] {
+			if cand.pos > best.pos {
+				best = cand
+			}
+		}
+		report(best.node)
+	}
+}
+
+func nameMatches(step Step, name string) bool {
+	if step.Attr != "" || step.Text {
+		return false
+	}
+	return step.Name == "*" || step.Name == name
+}
+
+func attrValue(attrs []pullparser.Attr, name string) (string, bool) {
+	for _, a := range attrs {
+		if a.Name == name || name == "*" {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// immediateOK evaluates the predicates that can be checked as soon as a
+// StartTag is seen: attribute equality/existence and position().
+func immediateOK(preds []Pred, attrs []pullparser.Attr, pos int) bool {
+	for _, pr := range preds {
+		switch {
+		case pr.Attr != "":
+			v, ok := attrValue(attrs, pr.Attr)
+			if !ok {
+				return false
+			}
+			if pr.HasValue && v != pr.Value {
+				return false
+			}
+		case pr.Pos != 0:
+			if pos != pr.Pos {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// deferredOK evaluates the predicates that require the element's full
+// subtree: contains()/starts-with() over text() or name(). last() is
+// handled separately by the parent frame once all siblings are known.
+func deferredOK(preds []Pred, node *Node) bool {
+	for _, pr := range preds {
+		if pr.Func == "" {
+			continue
+		}
+		subject := node.Text
+		if pr.FuncName {
+			subject = node.Name
+		} else if pr.FuncAttr != "" {
+			v, ok := attrValue(node.Attrs, pr.FuncAttr)
+			if !ok {
+				return false
+			}
+			subject = v
+		}
+		switch pr.Func {
+		case "contains":
+			if !strings.Contains(subject, pr.FuncStr) {
+				return false
+			}
+		case "starts-with":
+			if !strings.HasPrefix(subject, pr.FuncStr) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func hasLast(preds []Pred) bool {
+	for _, pr := range preds {
+		if pr.Last {
+			return true
+		}
+	}
+	return false
+}
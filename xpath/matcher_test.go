@@ -0,0 +1,83 @@
+//////////////////////////////////////////////////////////////////////////////
+// file: matcher_test.go
+//         Covers predicate evaluation in Matcher.enter, including the
+//         non-final-step case ("//book[@id='2']/title") that enter used to
+//         silently ignore.
+// last revision:	07/26/2026
+//////////////////////////////////////////////////////////////////////////////
+package xpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jschwartzman/xmlParse/pullparser"
+)
+
+const catalogXML = `<catalog>
+<book id="1"><title>Go</title></book>
+<book id="2"><title>Rust</title></book>
+<book id="3"><title>C</title></book>
+</catalog>`
+
+func evalQuery(t *testing.T, expr, doc string) []*Node {
+	t.Helper()
+	exprs, err := Compile([]string{expr})
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", expr, err)
+	}
+	var nodes []*Node
+	m := NewMatcher(exprs)
+	if _, err := m.Eval(pullparser.New(strings.NewReader(doc)), func(n *Node) {
+		nodes = append(nodes, n)
+	}); err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+	return nodes
+}
+
+func names(nodes []*Node) []string {
+	var out []string
+	for _, n := range nodes {
+		out = append(out, n.Text)
+	}
+	return out
+}
+
+func TestAttrPredicateOnNonFinalStep(t *testing.T) {
+	nodes := evalQuery(t, `//book[@id="2"]/title`, catalogXML)
+	if got := names(nodes); len(got) != 1 || got[0] != "Rust" {
+		t.Fatalf("//book[@id=\"2\"]/title = %v, want exactly [Rust]", got)
+	}
+}
+
+func TestPositionalPredicateOnNonFinalStep(t *testing.T) {
+	nodes := evalQuery(t, `//book[2]/title`, catalogXML)
+	if got := names(nodes); len(got) != 1 || got[0] != "Rust" {
+		t.Fatalf("//book[2]/title = %v, want exactly [Rust]", got)
+	}
+}
+
+func TestNoPredicateOnNonFinalStepStillMatchesAll(t *testing.T) {
+	nodes := evalQuery(t, `//book/title`, catalogXML)
+	if got := names(nodes); len(got) != 3 {
+		t.Fatalf("//book/title = %v, want all 3 titles", got)
+	}
+}
+
+func TestAttrPredicateOnFinalStep(t *testing.T) {
+	nodes := evalQuery(t, `//book[@id="2"]`, catalogXML)
+	if len(nodes) != 1 || nodes[0].Kind != ElementNode {
+		t.Fatalf("//book[@id=\"2\"] = %v, want exactly one <book> element", nodes)
+	}
+	if len(nodes[0].Children) != 1 || nodes[0].Children[0].Text != "Rust" {
+		t.Fatalf("matched <book> = %+v, want child <title>Rust</title>", nodes[0])
+	}
+}
+
+func TestLastOnFinalStep(t *testing.T) {
+	nodes := evalQuery(t, `//book[last()]`, catalogXML)
+	if len(nodes) != 1 || len(nodes[0].Children) != 1 || nodes[0].Children[0].Text != "C" {
+		t.Fatalf("//book[last()] = %v, want the last <book> (title C)", nodes)
+	}
+}